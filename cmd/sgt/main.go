@@ -42,11 +42,14 @@ func usage() {
 func main() {
 	// Parse command line arguments.
 	var (
+		// noBoundsCheck specifies whether to disable bounds checks.
+		noBoundsCheck bool
 		// Output path of LLVM IR module.
 		output string
 		// quiet specifies whether to suppress non-error messages.
 		quiet bool
 	)
+	flag.BoolVar(&noBoundsCheck, "B", false, "disable bounds checking")
 	flag.StringVar(&output, "o", "", "output path of LLVM IR module (default: standard output)")
 	flag.BoolVar(&quiet, "q", false, "suppress non-error messages")
 	flag.Usage = usage
@@ -61,6 +64,10 @@ func main() {
 		dbg.SetOutput(ioutil.Discard)
 		irgen.SetDebugOutput(ioutil.Discard)
 	}
+	// Disable bounds checks if `-B` is set.
+	if noBoundsCheck {
+		irgen.SetBoundsCheck(false)
+	}
 
 	// Write to standard output or output file path if specified by -o flag.
 	w := os.Stdout
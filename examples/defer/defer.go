@@ -0,0 +1,39 @@
+package main
+
+func main() {
+	openCoded()
+	manyDefers()
+	deferInLoop()
+	println(namedResult())
+}
+
+func openCoded() {
+	defer println("open-coded: 2")
+	defer println("open-coded: 1")
+	println("open-coded: 0")
+}
+
+func manyDefers() {
+	defer println("many: 9")
+	defer println("many: 8")
+	defer println("many: 7")
+	defer println("many: 6")
+	defer println("many: 5")
+	defer println("many: 4")
+	defer println("many: 3")
+	defer println("many: 2")
+	defer println("many: 1")
+}
+
+func deferInLoop() {
+	for _, s := range []string{"loop: 3", "loop: 2", "loop: 1"} {
+		defer println(s)
+	}
+}
+
+func namedResult() (s string) {
+	defer func() {
+		s = "named result: " + s
+	}()
+	return "foo"
+}
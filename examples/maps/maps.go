@@ -0,0 +1,15 @@
+package main
+
+func main() {
+	m := map[string]string{
+		"foo": "bar",
+		"baz": "qux",
+	}
+	m["quux"] = "corge"
+	delete(m, "baz")
+	println(m["foo"])
+	println(m["quux"])
+	if _, ok := m["baz"]; !ok {
+		println("baz deleted")
+	}
+}
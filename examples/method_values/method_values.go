@@ -0,0 +1,18 @@
+package main
+
+func main() {
+	t := T{name: "bound"}
+	f := t.M
+	t.name = "changed"
+	println(f())
+	g := T.M
+	println(g(T{name: "thunk"}))
+}
+
+type T struct {
+	name string
+}
+
+func (t T) M() string {
+	return t.name
+}
@@ -0,0 +1,54 @@
+package main
+
+func main() {
+	println(recovered())
+	println(nested())
+	println(repanic())
+	println(indirect())
+}
+
+func recovered() (s string) {
+	defer func() {
+		s = "recovered: " + recover().(string)
+	}()
+	panic("foo")
+}
+
+func nested() (s string) {
+	defer func() {
+		s = "nested: " + s + ", " + recover().(string)
+	}()
+	defer func() {
+		defer func() {
+			s = recover().(string)
+		}()
+		panic("bar")
+	}()
+	panic("foo")
+}
+
+func repanic() (s string) {
+	defer func() {
+		s = "repanic: " + recover().(string)
+	}()
+	defer func() {
+		panic(recover())
+	}()
+	panic("baz")
+}
+
+func indirect() (s string) {
+	defer func() {
+		s = "indirect: " + recover().(string)
+	}()
+	defer func() {
+		if recoverHelper() == nil {
+			println("not recovered by helper")
+		}
+	}()
+	panic("qux")
+}
+
+func recoverHelper() interface{} {
+	return recover()
+}
@@ -0,0 +1,23 @@
+package main
+
+import "github.com/mewmew/skumgummitomte/examples/unnamed_types/p"
+
+type T struct{}
+
+func (T) m() {
+	println("main.T.m")
+}
+
+func main() {
+	if _, ok := p.Struct().(struct{ x int }); !ok {
+		println("p.Struct() is not of type struct{ x int } in main")
+	}
+	v := p.Iface()
+	if _, ok := v.(interface{ m() }); !ok {
+		println("p.Iface() does not implement interface{ m() } in main")
+	}
+	var i interface{ m() } = T{}
+	i.m()
+	p.Call(v)
+	p.Call(T{})
+}
@@ -0,0 +1,22 @@
+package p
+
+type T struct{}
+
+func (T) m() {
+	println("p.T.m")
+}
+
+func Struct() interface{} {
+	return struct{ x int }{x: 1}
+}
+
+func Iface() interface{} {
+	var i interface{ m() } = T{}
+	return i
+}
+
+func Call(v interface{}) {
+	if i, ok := v.(interface{ m() }); ok {
+		i.m()
+	}
+}
@@ -0,0 +1,159 @@
+package irgen
+
+import (
+	"go/token"
+	"math/big"
+
+	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
+	irtypes "github.com/llir/llvm/ir/types"
+	irvalue "github.com/llir/llvm/ir/value"
+	"golang.org/x/tools/go/ssa"
+)
+
+// --- [ integer division ] ----------------------------------------------------
+
+// emitIntDiv emits the integer division (token.QUO) or remainder (token.REM)
+// of x by y, emitting to fn. The signed flag reports whether the operands have
+// signed integer type.
+//
+// Division by zero panics, as required by Go. The quotient of the most negative
+// value by -1 overflows, and is defined by Go to be equal to the dividend (and
+// the remainder to be 0), whereas the result is undefined in LLVM IR.
+func (fn *Func) emitIntDiv(op token.Token, x, y irvalue.Value, signed bool) irValueInstruction {
+	fn.emitDivideCheck(y)
+	if !signed {
+		if op == token.REM {
+			return fn.cur.NewURem(x, y)
+		}
+		return fn.cur.NewUDiv(x, y)
+	}
+	typ := x.Type().(*irtypes.IntType)
+	if c, ok := y.(*irconstant.Int); ok && c.X.Cmp(big.NewInt(-1)) != 0 {
+		// Constant divisor other than -1; no overflow.
+		if op == token.REM {
+			return fn.cur.NewSRem(x, y)
+		}
+		return fn.cur.NewSDiv(x, y)
+	}
+	// Divide by 1 instead of -1 to prevent overflow, and negate the quotient.
+	minusOne := irconstant.NewInt(typ, -1)
+	isMinusOne := fn.cur.NewICmp(irenum.IPredEQ, y, minusOne)
+	divisor := fn.cur.NewSelect(isMinusOne, irconstant.NewInt(typ, 1), y)
+	if op == token.REM {
+		// x % -1 == x % 1 == 0
+		return fn.cur.NewSRem(x, divisor)
+	}
+	quo := fn.cur.NewSDiv(x, divisor)
+	neg := fn.cur.NewSub(irconstant.NewInt(typ, 0), x)
+	return fn.cur.NewSelect(isMinusOne, neg, quo)
+}
+
+// emitDivideCheck emits a check that the divisor y is not zero, emitting to fn.
+//
+// No check is emitted if the divisor is a non-zero constant.
+func (fn *Func) emitDivideCheck(y irvalue.Value) {
+	if c, ok := y.(*irconstant.Int); ok && c.X.Sign() != 0 {
+		return
+	}
+	zero := irconstant.NewInt(y.Type().(*irtypes.IntType), 0)
+	isZero := fn.cur.NewICmp(irenum.IPredEQ, y, zero)
+	failBlock := fn.newAuxBlock("divide.fail")
+	okBlock := fn.newAuxBlock("divide.ok")
+	fn.cur.NewCondBr(isZero, failBlock, okBlock)
+	panicdivide := fn.m.getPredeclaredFunc("runtime.panicdivide")
+	failBlock.NewCall(panicdivide)
+	failBlock.NewUnreachable()
+	fn.cur = okBlock
+}
+
+// --- [ shift ] ---------------------------------------------------------------
+
+// emitShift emits the left (token.SHL) or right (token.SHR) shift of x by the
+// shift count y, emitting to fn. The signedX and signedY flags report whether x
+// and y have signed integer type respectively. The shift count may be of any
+// integer type.
+//
+// Shifts by negative shift counts panic, as required by Go. Shifts by counts of
+// at least the bit size of x shift out all bits (producing 0, or -1 for right
+// shifts of negative signed integers), whereas the result is poison in LLVM IR.
+func (fn *Func) emitShift(op token.Token, x, y irvalue.Value, signedX, signedY bool) irValueInstruction {
+	typ := x.Type().(*irtypes.IntType)
+	if signedY {
+		fn.emitShiftCheck(y)
+	}
+	if c, ok := y.(*irconstant.Int); ok && c.X.Sign() >= 0 && c.X.IsInt64() && c.X.Int64() < int64(typ.BitSize) {
+		// Constant shift count in range.
+		count := irconstant.NewInt(typ, c.X.Int64())
+		switch {
+		case op == token.SHL:
+			return fn.cur.NewShl(x, count)
+		case signedX:
+			return fn.cur.NewAShr(x, count)
+		default:
+			return fn.cur.NewLShr(x, count)
+		}
+	}
+	// Convert shift count to the type of x; y is known to be non-negative.
+	yType := y.Type().(*irtypes.IntType)
+	var count irvalue.Value
+	var tooLarge irvalue.Value // y >= bit size of x
+	switch {
+	case yType.BitSize > typ.BitSize:
+		tooLarge = fn.cur.NewICmp(irenum.IPredUGE, y, irconstant.NewInt(yType, int64(typ.BitSize)))
+		count = fn.cur.NewTrunc(y, typ)
+	case yType.BitSize < typ.BitSize:
+		count = fn.cur.NewZExt(y, typ)
+		tooLarge = fn.cur.NewICmp(irenum.IPredUGE, count, irconstant.NewInt(typ, int64(typ.BitSize)))
+	default:
+		count = y
+		tooLarge = fn.cur.NewICmp(irenum.IPredUGE, count, irconstant.NewInt(typ, int64(typ.BitSize)))
+	}
+	zero := irconstant.NewInt(typ, 0)
+	switch {
+	case op == token.SHL:
+		shl := fn.cur.NewShl(x, count)
+		return fn.cur.NewSelect(tooLarge, zero, shl)
+	case signedX:
+		// Shift in sign bits by shifting by bit size - 1 at most.
+		max := irconstant.NewInt(typ, int64(typ.BitSize-1))
+		count = fn.cur.NewSelect(tooLarge, max, count)
+		return fn.cur.NewAShr(x, count)
+	default:
+		lshr := fn.cur.NewLShr(x, count)
+		return fn.cur.NewSelect(tooLarge, zero, lshr)
+	}
+}
+
+// emitShiftCount returns the shift count of the given Go shift count value,
+// emitting to fn. The signed flag reports whether the shift count has signed
+// integer type.
+func (fn *Func) emitShiftCount(goCount ssa.Value) (count irvalue.Value, signed bool) {
+	if conv, ok := goCount.(*ssa.Convert); ok && conv.Pos() == token.NoPos && isSigned(conv.X.Type()) {
+		// Go SSA converts signed shift counts to uint64; use the original shift
+		// count to detect negative shift counts. Conversions synthesized by Go
+		// SSA have no position, as opposed to explicit conversions of the shift
+		// count (e.g. `x << uint(n)`), which are used as written.
+		return fn.useValue(conv.X), true
+	}
+	return fn.useValue(goCount), isSigned(goCount.Type())
+}
+
+// emitShiftCheck emits a check that the shift count y of signed integer type is
+// not negative, emitting to fn.
+//
+// No check is emitted if the shift count is a non-negative constant.
+func (fn *Func) emitShiftCheck(y irvalue.Value) {
+	if c, ok := y.(*irconstant.Int); ok && c.X.Sign() >= 0 {
+		return
+	}
+	zero := irconstant.NewInt(y.Type().(*irtypes.IntType), 0)
+	isNeg := fn.cur.NewICmp(irenum.IPredSLT, y, zero)
+	failBlock := fn.newAuxBlock("shift.fail")
+	okBlock := fn.newAuxBlock("shift.ok")
+	fn.cur.NewCondBr(isNeg, failBlock, okBlock)
+	panicshift := fn.m.getPredeclaredFunc("runtime.panicshift")
+	failBlock.NewCall(panicshift)
+	failBlock.NewUnreachable()
+	fn.cur = okBlock
+}
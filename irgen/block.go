@@ -25,6 +25,21 @@ func (fn *Func) getBlock(goBlock *ssa.BasicBlock) *ir.Block {
 	return block
 }
 
+// getExitBlock returns the last LLVM IR basic block generated for the given Go
+// SSA basic block; i.e. the LLVM IR basic block holding the terminator of the Go
+// SSA basic block.
+//
+// Pre-condition: compile basic block goBlock of fn.
+func (fn *Func) getExitBlock(goBlock *ssa.BasicBlock) *ir.Block {
+	block, ok := fn.exits[goBlock]
+	if !ok {
+		// Pre-condition invalidated, basic block not yet compiled. This is a
+		// fatal error and indicates a bug in irgen.
+		panic(fmt.Errorf("unable to locate exit LLVM IR basic block of Go SSA basic block with index %d (%q)", goBlock.Index, goBlock.Comment))
+	}
+	return block
+}
+
 // --- [ index ] ---------------------------------------------------------------
 
 // indexBlock indexes the given Go SSA basic block, creating a corresponding
@@ -55,9 +70,19 @@ func (fn *Func) emitBlock(goBlock *ssa.BasicBlock) error {
 			return errors.WithStack(err)
 		}
 	}
+	fn.exits[goBlock] = fn.cur
 	return nil
 }
 
+// newAuxBlock returns a new auxiliary LLVM IR basic block of fn, used when
+// lowering a single Go SSA instruction to control flow (e.g. runtime checks).
+// The name of the basic block is derived from the given description.
+func (fn *Func) newAuxBlock(desc string) *ir.Block {
+	fn.nauxBlocks++
+	blockName := fmt.Sprintf("%s_%d", desc, fn.nauxBlocks)
+	return fn.Func.NewBlock(blockName)
+}
+
 // ### [ Helper functions ] ####################################################
 
 // getBlockName returns the LLVM IR basic block name based on the given Go SSA
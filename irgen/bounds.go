@@ -0,0 +1,116 @@
+package irgen
+
+import (
+	"go/token"
+
+	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
+	irtypes "github.com/llir/llvm/ir/types"
+	irvalue "github.com/llir/llvm/ir/value"
+	"golang.org/x/tools/go/ssa"
+)
+
+// boundsKind specifies the kind of a bounds check. The kind is used by
+// runtime.panicbounds to format the error message of a failed bounds check.
+type boundsKind uint8
+
+// Bounds check kinds.
+const (
+	// s[x]: 0 <= x < len(s)
+	boundsIndex boundsKind = iota
+	// s[?:x]: 0 <= x <= len(s) (string or array)
+	boundsSliceAlen
+	// s[?:x]: 0 <= x <= cap(s) (slice)
+	boundsSliceAcap
+	// s[x:y]: 0 <= x <= y
+	boundsSliceB
+	// s[?:?:x]: 0 <= x <= len(s) (array)
+	boundsSlice3Alen
+	// s[?:?:x]: 0 <= x <= cap(s) (slice)
+	boundsSlice3Acap
+	// s[?:x:y]: 0 <= x <= y
+	boundsSlice3B
+	// s[x:y:?]: 0 <= x <= y
+	boundsSlice3C
+)
+
+// --- [ bounds check ] --------------------------------------------------------
+
+// emitBoundsCheck emits a bounds check of the index x against y of the given
+// bounds check kind, emitting to fn. Index checks (boundsIndex) require x < y,
+// while slice checks require x <= y. The signed flag reports whether x has
+// signed integer type, in which case negative indices are reported as such.
+//
+// No bounds check is emitted if bounds checks are disabled, or if the bounds
+// check is known to succeed at compile time.
+func (fn *Func) emitBoundsCheck(x irvalue.Value, signed bool, y irvalue.Value, kind boundsKind) {
+	if !boundsCheck {
+		return
+	}
+	if inBounds(x, y, kind) {
+		return
+	}
+	// Unsigned comparison, as negative indices are out of range.
+	pred := irenum.IPredULE
+	if kind == boundsIndex {
+		pred = irenum.IPredULT
+	}
+	ok := fn.cur.NewICmp(pred, x, y)
+	failBlock := fn.newAuxBlock("bounds.fail")
+	okBlock := fn.newAuxBlock("bounds.ok")
+	fn.cur.NewCondBr(ok, okBlock, failBlock)
+	panicbounds := fn.m.getPredeclaredFunc("runtime.panicbounds")
+	uint8Type := fn.m.irTypeFromName("uint8").(*irtypes.IntType)
+	failBlock.NewCall(panicbounds, x, irconstant.NewBool(signed), y, irconstant.NewInt(uint8Type, int64(kind)))
+	failBlock.NewUnreachable()
+	fn.cur = okBlock
+}
+
+// emitIntIndex converts the given Go index value of integer type to int,
+// emitting to fn. The signed flag reports whether the index has signed integer
+// type.
+func (fn *Func) emitIntIndex(goIndex ssa.Value) (index irvalue.Value, signed bool) {
+	index = fn.useValue(goIndex)
+	goType := goIndex.Type()
+	if conv, ok := goIndex.(*ssa.Convert); ok && conv.Pos() == token.NoPos && isIntegerType(conv.X.Type()) {
+		// Go SSA converts the indices of index and indexaddr instructions to
+		// int; use the original type to report unsigned indices (e.g. uint64)
+		// out of range of int. Conversions synthesized by Go SSA have no
+		// position, as opposed to explicit conversions of the index (e.g.
+		// `a[int(u)]`), which are used as written.
+		goType = conv.X.Type()
+	}
+	signed = isSigned(goType)
+	intType := fn.m.irTypeFromName("int").(*irtypes.IntType)
+	indexType := index.Type().(*irtypes.IntType)
+	if indexType.BitSize >= intType.BitSize {
+		return index, signed
+	}
+	if signed {
+		return fn.cur.NewSExt(index, intType), signed
+	}
+	return fn.cur.NewZExt(index, intType), signed
+}
+
+// ### [ Helper functions ] ####################################################
+
+// inBounds reports whether the bounds check of the index x against y of the
+// given bounds check kind is known to succeed at compile time.
+func inBounds(x, y irvalue.Value, kind boundsKind) bool {
+	c, ok := x.(*irconstant.Int)
+	if !ok || c.X.Sign() < 0 {
+		return false
+	}
+	if c.X.Sign() == 0 && kind != boundsIndex {
+		// 0 <= y holds for lengths and capacities.
+		return true
+	}
+	d, ok := y.(*irconstant.Int)
+	if !ok {
+		return false
+	}
+	if kind == boundsIndex {
+		return c.X.Cmp(d.X) < 0
+	}
+	return c.X.Cmp(d.X) <= 0
+}
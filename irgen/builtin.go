@@ -2,48 +2,381 @@ package irgen
 
 import (
 	"fmt"
-	"strings"
+	gotypes "go/types"
 
 	"github.com/llir/llvm/ir"
+	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
 	irtypes "github.com/llir/llvm/ir/types"
 	irvalue "github.com/llir/llvm/ir/value"
 )
 
 // synthLen synthesizes a builtin `len` function based on the given argument
 // type, emitting to m.
-func (m *Module) synthLen(argType irtypes.Type) *ir.Func {
+func (m *Module) synthLen(goArgType gotypes.Type) *ir.Func {
 	dbg.Println("synthLen")
 	// Define `len(T)` function if not present.
-	typeName := argType.Name()
+	typeName := m.typeName(goArgType, nil)
 	lenFuncName := fmt.Sprintf("len(%s)", typeName)
 	if lenFunc, ok := m.predeclaredFuncs[lenFuncName]; ok {
 		return lenFunc
 	}
 	retType := m.irTypeFromName("int")
+	argType := m.irTypeFromGo(goArgType)
 	arg := ir.NewParam("v", argType)
 	lenFunc := m.Module.NewFunc(lenFuncName, retType, arg)
 	entry := lenFunc.NewBlock("entry")
 	var length irvalue.Value
-	switch argType := argType.(type) {
-	case *irtypes.StructType:
-		switch {
-		// string
-		case argType.Name() == "string":
-			lengthField := entry.NewExtractValue(arg, 1)
-			addMetadata(lengthField, "field", "len")
-			length = lengthField
-		// slice
-		case strings.HasPrefix(argType.Name(), "[]"):
-			lengthField := entry.NewExtractValue(arg, 1)
-			addMetadata(lengthField, "field", "len")
-			length = lengthField
-		default:
-			panic(fmt.Errorf("support for type %T (%q) as argument to builtin len function not yet implemented", argType, argType.Name()))
-		}
+	switch {
+	// string
+	case isStringType(goArgType):
+		lengthField := entry.NewExtractValue(arg, 1)
+		addMetadata(lengthField, "field", "len")
+		length = lengthField
+	// slice
+	case isSliceType(goArgType):
+		lengthField := entry.NewExtractValue(arg, 1)
+		addMetadata(lengthField, "field", "len")
+		length = lengthField
 	default:
-		panic(fmt.Errorf("support for type %T (%q) as argument to builtin len function not yet implemented", argType, argType.Name()))
+		panic(fmt.Errorf("support for type %v as argument to builtin len function not yet implemented", goArgType))
 	}
 	entry.NewRet(length)
 	m.predeclaredFuncs[lenFuncName] = lenFunc
 	return lenFunc
 }
+
+// synthCap synthesizes a builtin `cap` function based on the given argument
+// type, emitting to m.
+func (m *Module) synthCap(goArgType gotypes.Type) *ir.Func {
+	dbg.Println("synthCap")
+	// Define `cap(T)` function if not present.
+	typeName := m.typeName(goArgType, nil)
+	capFuncName := fmt.Sprintf("cap(%s)", typeName)
+	if capFunc, ok := m.predeclaredFuncs[capFuncName]; ok {
+		return capFunc
+	}
+	retType := m.irTypeFromName("int")
+	argType := m.irTypeFromGo(goArgType)
+	arg := ir.NewParam("v", argType)
+	capFunc := m.Module.NewFunc(capFuncName, retType, arg)
+	entry := capFunc.NewBlock("entry")
+	var capacity irvalue.Value
+	switch {
+	// slice
+	case isSliceType(goArgType):
+		capacityField := entry.NewExtractValue(arg, 2)
+		addMetadata(capacityField, "field", "cap")
+		capacity = capacityField
+	default:
+		panic(fmt.Errorf("support for type %v as argument to builtin cap function not yet implemented", goArgType))
+	}
+	entry.NewRet(capacity)
+	m.predeclaredFuncs[capFuncName] = capFunc
+	return capFunc
+}
+
+// synthNew synthesizes a builtin `new` function based on the given element
+// type, emitting to m.
+func (m *Module) synthNew(goElemType gotypes.Type) *ir.Func {
+	dbg.Println("synthNew")
+	// Define `new(T)` function if not present.
+	typeName := m.typeName(goElemType, nil)
+	newFuncName := fmt.Sprintf("new(%s)", typeName)
+	if newFunc, ok := m.predeclaredFuncs[newFuncName]; ok {
+		return newFunc
+	}
+	ptrType := irtypes.NewPointer(m.irTypeFromGo(goElemType))
+	retType := ptrType
+	newFunc := m.Module.NewFunc(newFuncName, retType)
+	// Synthesized functions may be emitted by several LLVM IR modules.
+	newFunc.Linkage = irenum.LinkageLinkOnceODR
+	entry := newFunc.NewBlock("entry")
+	allocaInst := entry.NewAlloca(ptrType.ElemType)
+	objectsizeFunc := m.getPredeclaredFunc("llvm.objectsize.i64")
+	bitCastInst := entry.NewBitCast(allocaInst, irtypes.I8Ptr)
+	objectsizeArgs := []irvalue.Value{
+		bitCastInst,      // object
+		irconstant.False, // min
+		irconstant.False, // nullunknown
+		irconstant.False, // dynamic
+	}
+	size := entry.NewCall(objectsizeFunc, objectsizeArgs...)
+	size.SetName("size")
+	cond := entry.NewICmp(irenum.IPredNE, size, irconstant.NewInt(irtypes.I64, -1))
+	success := newFunc.NewBlock("success")
+	fail := newFunc.NewBlock("fail")
+	entry.NewCondBr(cond, success, fail)
+	// Generate `success` basic block.
+	callocFunc := m.getPredeclaredFunc("calloc") // using calloc to zero initialize
+	args := []irvalue.Value{
+		irconstant.NewInt(irtypes.I64, 1),
+		size,
+	}
+	callInst := success.NewCall(callocFunc, args...)
+	result := success.NewBitCast(callInst, ptrType)
+	success.NewRet(result)
+	// Generate `fail` basic block.
+	goStringType := gotypes.Typ[gotypes.String]
+	msg := m.irValueFromGoStringLit(goStringType, fmt.Sprintf("unable to get size of type %s", typeString(goElemType)))
+	panicerrorFunc := m.getPredeclaredFunc("runtime.panicerror")
+	fail.NewCall(panicerrorFunc, msg)
+	fail.NewUnreachable()
+	// Add synthesized `new(T)` function to predeclared functions.
+	m.predeclaredFuncs[newFunc.Name()] = newFunc
+	return newFunc
+}
+
+// synthEqual synthesizes an `eq` function comparing the two values of the given
+// Go type pointed to by x and y, emitting to m. The boolean result reports
+// whether the values are equal. A nil function is returned if values of the
+// given Go type are not comparable.
+//
+//	func eq(T)(x, y unsafe.Pointer) bool
+func (m *Module) synthEqual(goType gotypes.Type) *ir.Func {
+	dbg.Println("synthEqual")
+	if !gotypes.Comparable(goType) {
+		return nil
+	}
+	// Define `eq(T)` function if not present.
+	typeName := m.typeName(goType, nil)
+	eqFuncName := fmt.Sprintf("eq(%s)", typeName)
+	if eqFunc, ok := m.predeclaredFuncs[eqFuncName]; ok {
+		return eqFunc
+	}
+	retType := m.irTypeFromName("bool")
+	x := ir.NewParam("x", irtypes.I8Ptr)
+	y := ir.NewParam("y", irtypes.I8Ptr)
+	eqFunc := m.Module.NewFunc(eqFuncName, retType, x, y)
+	// Synthesized functions may be emitted by several LLVM IR modules.
+	eqFunc.Linkage = irenum.LinkageLinkOnceODR
+	m.predeclaredFuncs[eqFuncName] = eqFunc
+	entry := eqFunc.NewBlock("entry")
+	ptrType := irtypes.NewPointer(m.irTypeFromGo(goType))
+	xPtr := entry.NewBitCast(x, ptrType)
+	yPtr := entry.NewBitCast(y, ptrType)
+	exit, result := m.emitEqualAddr(eqFunc, entry, goType, xPtr, yPtr)
+	exit.NewRet(result)
+	return eqFunc
+}
+
+// emitEqualAddr emits an equality comparison of the values of the given Go type
+// pointed to by x and y, starting at the basic block cur of f. The basic block
+// at the end of the comparison is returned together with the boolean result.
+func (m *Module) emitEqualAddr(f *ir.Func, cur *ir.Block, goType gotypes.Type, x, y irvalue.Value) (*ir.Block, irvalue.Value) {
+	typ := m.irTypeFromGo(goType)
+	switch goType := goType.Underlying().(type) {
+	case *gotypes.Basic:
+		if goType.Info()&gotypes.IsString != 0 {
+			// Load values of defined string types as strings.
+			typ = m.irTypeFromName("string")
+			x = cur.NewBitCast(x, irtypes.NewPointer(typ))
+			y = cur.NewBitCast(y, irtypes.NewPointer(typ))
+		}
+		xv := cur.NewLoad(typ, x)
+		yv := cur.NewLoad(typ, y)
+		switch {
+		case goType.Info()&gotypes.IsString != 0:
+			strequal := m.getPredeclaredFunc("runtime.strequal")
+			return cur, cur.NewCall(strequal, xv, yv)
+		case goType.Info()&gotypes.IsFloat != 0:
+			return cur, cur.NewFCmp(irenum.FPredOEQ, xv, yv)
+		case goType.Info()&gotypes.IsComplex != 0:
+			xReal := cur.NewExtractValue(xv, 0)
+			yReal := cur.NewExtractValue(yv, 0)
+			xImag := cur.NewExtractValue(xv, 1)
+			yImag := cur.NewExtractValue(yv, 1)
+			real := cur.NewFCmp(irenum.FPredOEQ, xReal, yReal)
+			imag := cur.NewFCmp(irenum.FPredOEQ, xImag, yImag)
+			return cur, cur.NewAnd(real, imag)
+		default:
+			return cur, cur.NewICmp(irenum.IPredEQ, xv, yv)
+		}
+	case *gotypes.Pointer, *gotypes.Chan:
+		xv := cur.NewLoad(typ, x)
+		yv := cur.NewLoad(typ, y)
+		return cur, cur.NewICmp(irenum.IPredEQ, xv, yv)
+	case *gotypes.Interface:
+		xv := cur.NewLoad(typ, x)
+		yv := cur.NewLoad(typ, y)
+		xTab := cur.NewExtractValue(xv, 0)
+		xData := cur.NewExtractValue(xv, 1)
+		yTab := cur.NewExtractValue(yv, 0)
+		yData := cur.NewExtractValue(yv, 1)
+		ifaceeq := m.getPredeclaredFunc("runtime.ifaceeq")
+		return cur, cur.NewCall(ifaceeq, xTab, xData, yTab, yData)
+	case *gotypes.Struct:
+		// Compare non-blank fields.
+		var result irvalue.Value = irconstant.True
+		zero := irconstant.NewInt(irtypes.I32, 0)
+		for i := 0; i < goType.NumFields(); i++ {
+			goField := goType.Field(i)
+			if goField.Name() == "_" {
+				continue
+			}
+			index := irconstant.NewInt(irtypes.I32, int64(i))
+			xField := cur.NewGetElementPtr(typ, x, zero, index)
+			yField := cur.NewGetElementPtr(typ, y, zero, index)
+			var fieldResult irvalue.Value
+			cur, fieldResult = m.emitEqualAddr(f, cur, goField.Type(), xField, yField)
+			result = cur.NewAnd(result, fieldResult)
+		}
+		return cur, result
+	case *gotypes.Array:
+		// Compare elements; stop at the first mismatch.
+		//
+		//    for i := 0; i < len(x); i++ {
+		//       if x[i] != y[i] {
+		//          return false
+		//       }
+		//    }
+		//    return true
+		intType := m.irTypeFromName("int").(*irtypes.IntType)
+		zero := irconstant.NewInt(intType, 0)
+		loopCond := f.NewBlock("")
+		loopBody := f.NewBlock("")
+		loopPost := f.NewBlock("")
+		loopExit := f.NewBlock("")
+		pre := cur
+		pre.NewBr(loopCond)
+		i := loopCond.NewPhi(ir.NewIncoming(zero, pre))
+		length := irconstant.NewInt(intType, goType.Len())
+		cond := loopCond.NewICmp(irenum.IPredSLT, i, length)
+		loopCond.NewCondBr(cond, loopBody, loopExit)
+		xElem := loopBody.NewGetElementPtr(typ, x, zero, i)
+		yElem := loopBody.NewGetElementPtr(typ, y, zero, i)
+		bodyEnd, elemResult := m.emitEqualAddr(f, loopBody, goType.Elem(), xElem, yElem)
+		bodyEnd.NewCondBr(elemResult, loopPost, loopExit)
+		next := loopPost.NewAdd(i, irconstant.NewInt(intType, 1))
+		loopPost.NewBr(loopCond)
+		i.Incs = append(i.Incs, ir.NewIncoming(next, loopPost))
+		result := loopExit.NewPhi(ir.NewIncoming(irconstant.True, loopCond), ir.NewIncoming(irconstant.False, bodyEnd))
+		return loopExit, result
+	default:
+		panic(fmt.Errorf("support for comparing values of type %T (%v) not yet implemented", goType, goType))
+	}
+}
+
+// synthHash synthesizes a `hash` function hashing the value of the given Go
+// type pointed to by p, emitting to m. A nil function is returned if values of
+// the given Go type are not comparable, and thus not hashable.
+//
+//	func hash(T)(p unsafe.Pointer, seed uint64) uint64
+func (m *Module) synthHash(goType gotypes.Type) *ir.Func {
+	dbg.Println("synthHash")
+	if !gotypes.Comparable(goType) {
+		return nil
+	}
+	// Define `hash(T)` function if not present.
+	typeName := m.typeName(goType, nil)
+	hashFuncName := fmt.Sprintf("hash(%s)", typeName)
+	if hashFunc, ok := m.predeclaredFuncs[hashFuncName]; ok {
+		return hashFunc
+	}
+	retType := m.irTypeFromName("uint64")
+	p := ir.NewParam("p", irtypes.I8Ptr)
+	seed := ir.NewParam("seed", retType)
+	hashFunc := m.Module.NewFunc(hashFuncName, retType, p, seed)
+	// Synthesized functions may be emitted by several LLVM IR modules.
+	hashFunc.Linkage = irenum.LinkageLinkOnceODR
+	m.predeclaredFuncs[hashFuncName] = hashFunc
+	entry := hashFunc.NewBlock("entry")
+	ptrType := irtypes.NewPointer(m.irTypeFromGo(goType))
+	ptr := entry.NewBitCast(p, ptrType)
+	exit, result := m.emitHashAddr(hashFunc, entry, goType, ptr, seed)
+	exit.NewRet(result)
+	return hashFunc
+}
+
+// emitHashAddr emits a hash of the value of the given Go type pointed to by p,
+// starting at the basic block cur of f. The hash of each value is chained
+// through the seed. The basic block at the end of the hash is returned
+// together with the resulting hash.
+func (m *Module) emitHashAddr(f *ir.Func, cur *ir.Block, goType gotypes.Type, p, seed irvalue.Value) (*ir.Block, irvalue.Value) {
+	typ := m.irTypeFromGo(goType)
+	i8Ptr := cur.NewBitCast(p, irtypes.I8Ptr)
+	switch goType := goType.Underlying().(type) {
+	case *gotypes.Basic:
+		switch {
+		case goType.Info()&gotypes.IsString != 0:
+			strhash := m.getPredeclaredFunc("runtime.strhash")
+			return cur, cur.NewCall(strhash, i8Ptr, seed)
+		case goType.Kind() == gotypes.Float32:
+			f32hash := m.getPredeclaredFunc("runtime.f32hash")
+			return cur, cur.NewCall(f32hash, i8Ptr, seed)
+		case goType.Kind() == gotypes.Float64:
+			f64hash := m.getPredeclaredFunc("runtime.f64hash")
+			return cur, cur.NewCall(f64hash, i8Ptr, seed)
+		case goType.Info()&gotypes.IsComplex != 0:
+			// Hash real and imaginary parts.
+			floatHashName := "runtime.f64hash"
+			if goType.Kind() == gotypes.Complex64 {
+				floatHashName = "runtime.f32hash"
+			}
+			floatHash := m.getPredeclaredFunc(floatHashName)
+			zero := irconstant.NewInt(irtypes.I32, 0)
+			one := irconstant.NewInt(irtypes.I32, 1)
+			real := cur.NewGetElementPtr(typ, p, zero, zero)
+			realHash := cur.NewCall(floatHash, cur.NewBitCast(real, irtypes.I8Ptr), seed)
+			imag := cur.NewGetElementPtr(typ, p, zero, one)
+			return cur, cur.NewCall(floatHash, cur.NewBitCast(imag, irtypes.I8Ptr), realHash)
+		default:
+			return cur, m.emitMemHash(cur, typ, i8Ptr, seed)
+		}
+	case *gotypes.Pointer, *gotypes.Chan, *gotypes.Map:
+		return cur, m.emitMemHash(cur, typ, i8Ptr, seed)
+	case *gotypes.Interface:
+		interhash := m.getPredeclaredFunc("runtime.interhash")
+		return cur, cur.NewCall(interhash, i8Ptr, seed)
+	case *gotypes.Struct:
+		// Hash non-blank fields.
+		var result irvalue.Value = seed
+		zero := irconstant.NewInt(irtypes.I32, 0)
+		for i := 0; i < goType.NumFields(); i++ {
+			goField := goType.Field(i)
+			if goField.Name() == "_" {
+				continue
+			}
+			index := irconstant.NewInt(irtypes.I32, int64(i))
+			field := cur.NewGetElementPtr(typ, p, zero, index)
+			cur, result = m.emitHashAddr(f, cur, goField.Type(), field, result)
+		}
+		return cur, result
+	case *gotypes.Array:
+		// Hash elements.
+		//
+		//    h := seed
+		//    for i := 0; i < len(p); i++ {
+		//       h = hash(&p[i], h)
+		//    }
+		//    return h
+		intType := m.irTypeFromName("int").(*irtypes.IntType)
+		zero := irconstant.NewInt(intType, 0)
+		loopCond := f.NewBlock("")
+		loopBody := f.NewBlock("")
+		loopExit := f.NewBlock("")
+		pre := cur
+		pre.NewBr(loopCond)
+		i := loopCond.NewPhi(ir.NewIncoming(zero, pre))
+		h := loopCond.NewPhi(ir.NewIncoming(seed, pre))
+		length := irconstant.NewInt(intType, goType.Len())
+		cond := loopCond.NewICmp(irenum.IPredSLT, i, length)
+		loopCond.NewCondBr(cond, loopBody, loopExit)
+		elem := loopBody.NewGetElementPtr(typ, p, zero, i)
+		bodyEnd, elemHash := m.emitHashAddr(f, loopBody, goType.Elem(), elem, h)
+		next := bodyEnd.NewAdd(i, irconstant.NewInt(intType, 1))
+		bodyEnd.NewBr(loopCond)
+		i.Incs = append(i.Incs, ir.NewIncoming(next, bodyEnd))
+		h.Incs = append(h.Incs, ir.NewIncoming(elemHash, bodyEnd))
+		return loopExit, h
+	default:
+		panic(fmt.Errorf("support for hashing values of type %T (%v) not yet implemented", goType, goType))
+	}
+}
+
+// emitMemHash emits a hash of the memory contents of the value of the given
+// LLVM IR type pointed to by p, emitting to cur.
+func (m *Module) emitMemHash(cur *ir.Block, typ irtypes.Type, p, seed irvalue.Value) irvalue.Value {
+	memhash := m.getPredeclaredFunc("runtime.memhash")
+	return cur.NewCall(memhash, p, m.sizeof(typ), seed)
+}
@@ -0,0 +1,117 @@
+package irgen
+
+import (
+	"fmt"
+	gotypes "go/types"
+
+	"github.com/llir/llvm/ir"
+	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
+	irtypes "github.com/llir/llvm/ir/types"
+	irvalue "github.com/llir/llvm/ir/value"
+	"golang.org/x/tools/go/ssa"
+)
+
+// --- [ function value ] ------------------------------------------------------
+
+// getFuncValue returns the LLVM IR function value corresponding to the given Go
+// SSA function (without free variables) used as a value, emitting to m.
+func (m *Module) getFuncValue(goFunc *ssa.Function) irconstant.Constant {
+	if len(goFunc.FreeVars) > 0 {
+		// Closures are created by makeclosure instructions.
+		panic(fmt.Errorf("unable to use closure %q with free variables as function value", m.fullName(goFunc)))
+	}
+	f := m.irValueFromGoFunc(goFunc)
+	wrapper := m.synthFuncValueWrapper(f)
+	typ := m.newFuncValueType(wrapper.Sig)
+	return irconstant.NewStruct(typ, wrapper, irconstant.NewNull(irtypes.I8Ptr))
+}
+
+// synthFuncValueWrapper synthesizes a wrapper function of the given LLVM IR
+// function, for use as the code pointer of function values, emitting to m. The
+// wrapper function takes a context pointer as first argument, which is ignored,
+// and forwards the remaining arguments to f.
+//
+//	func f$funcval(ctx unsafe.Pointer, args...) results
+func (m *Module) synthFuncValueWrapper(f *ir.Func) *ir.Func {
+	dbg.Println("synthFuncValueWrapper")
+	// Define `f$funcval` function if not present.
+	wrapperName := fmt.Sprintf("%s$funcval", f.Name())
+	if wrapper, ok := m.predeclaredFuncs[wrapperName]; ok {
+		return wrapper
+	}
+	ctx := ir.NewParam("ctx", irtypes.I8Ptr)
+	params := []*ir.Param{ctx}
+	var args []irvalue.Value
+	for _, param := range f.Params {
+		arg := ir.NewParam(param.Name(), param.Typ)
+		params = append(params, arg)
+		args = append(args, arg)
+	}
+	wrapper := m.Module.NewFunc(wrapperName, f.Sig.RetType, params...)
+	wrapper.Sig.Variadic = f.Sig.Variadic
+	// Synthesized functions may be emitted by several LLVM IR modules.
+	wrapper.Linkage = irenum.LinkageLinkOnceODR
+	entry := wrapper.NewBlock("entry")
+	result := entry.NewCall(f, args...)
+	if irtypes.Equal(f.Sig.RetType, irtypes.Void) {
+		entry.NewRet(nil)
+	} else {
+		entry.NewRet(result)
+	}
+	m.predeclaredFuncs[wrapperName] = wrapper
+	return wrapper
+}
+
+// emitFuncValueCallee returns the callee and context pointer of the given
+// function value, emitting to fn.
+func (fn *Func) emitFuncValueCallee(funcValue irvalue.Value) (callee, ctx irvalue.Value) {
+	code := fn.cur.NewExtractValue(funcValue, 0)
+	addMetadata(code, "field", "code")
+	ctxField := fn.cur.NewExtractValue(funcValue, 1)
+	addMetadata(ctxField, "field", "ctx")
+	return code, ctxField
+}
+
+// --- [ closure context ] -----------------------------------------------------
+
+// emitFreeVars indexes the free variables of fn by loading them from the given
+// context pointer, emitting to fn.
+func (fn *Func) emitFreeVars(ctx irvalue.Value) {
+	ctxType := fn.m.irTypeFromGo(closureContextType(fn.goFunc))
+	ctxPtr := fn.cur.NewBitCast(ctx, irtypes.NewPointer(ctxType))
+	zero := irconstant.NewInt(irtypes.I64, 0)
+	for i, goFreeVar := range fn.goFunc.FreeVars {
+		indices := []irvalue.Value{
+			zero,
+			irconstant.NewInt(irtypes.I32, int64(i)),
+		}
+		ptr := fn.cur.NewGetElementPtr(ctxType, ctxPtr, indices...)
+		freeVarType := fn.m.irTypeFromGo(goFreeVar.Type())
+		freeVar := fn.cur.NewLoad(freeVarType, ptr)
+		addMetadata(freeVar, "var_name", goFreeVar.Name())
+		fn.setLocal(goFreeVar, freeVar)
+	}
+}
+
+// closureContextType returns the Go struct type of the context of the given
+// closure, which holds one field per free variable.
+func closureContextType(goFunc *ssa.Function) *gotypes.Struct {
+	var goFields []*gotypes.Var
+	for i, goFreeVar := range goFunc.FreeVars {
+		// Use synthetic field names, as the names of free variables may collide
+		// (e.g. same variable name captured from different scopes).
+		fieldName := fmt.Sprintf("fv%d", i)
+		goField := gotypes.NewField(goFreeVar.Pos(), nil, fieldName, goFreeVar.Type(), false)
+		goFields = append(goFields, goField)
+	}
+	return gotypes.NewStruct(goFields, nil)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// isFuncType reports whether the given Go type is a function type.
+func isFuncType(goType gotypes.Type) bool {
+	_, ok := goType.Underlying().(*gotypes.Signature)
+	return ok
+}
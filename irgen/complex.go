@@ -0,0 +1,162 @@
+package irgen
+
+import (
+	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
+	irtypes "github.com/llir/llvm/ir/types"
+	irvalue "github.com/llir/llvm/ir/value"
+	"golang.org/x/tools/go/ssa"
+)
+
+// --- [ complex ] -------------------------------------------------------------
+
+// emitComplexBuiltin compiles the given call to the builtin `complex` function
+// with the specified arguments, emitting to fn.
+func (fn *Func) emitComplexBuiltin(goInst *ssa.Call, args []irvalue.Value) error {
+	dbg.Println("emitComplexBuiltin")
+	typ := fn.m.irTypeFromGo(goInst.Type()).(*irtypes.StructType)
+	inst := fn.emitComplex(typ, args[0], args[1])
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ real and imag ] -------------------------------------------------------
+
+// emitRealImag compiles the given call to the builtin `real` or `imag` function
+// with the specified argument, emitting to fn.
+func (fn *Func) emitRealImag(goInst *ssa.Call, x irvalue.Value) error {
+	dbg.Println("emitRealImag")
+	index := uint64(0)
+	if goInst.Call.Value.Name() == "imag" {
+		index = 1
+	}
+	inst := fn.cur.NewExtractValue(x, index)
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ complex multiplication ] ----------------------------------------------
+
+// emitComplexMul emits the complex product x*y, emitting to fn.
+//
+// As done by gc, the product of complex64 values is computed in float64
+// precision and rounded to float32 once.
+func (fn *Func) emitComplexMul(x, y irvalue.Value) irValueInstruction {
+	typ := x.Type().(*irtypes.StructType)
+	a, b := fn.emitRealImagParts(x)
+	c, d := fn.emitRealImagParts(y)
+	a, b = fn.emitFloatExt(a), fn.emitFloatExt(b)
+	c, d = fn.emitFloatExt(c), fn.emitFloatExt(d)
+	// (a+bi)(c+di) = (ac-bd) + (ad+bc)i
+	real := fn.cur.NewFSub(fn.cur.NewFMul(a, c), fn.cur.NewFMul(b, d))
+	addMetadata(real, "comment", "real")
+	imag := fn.cur.NewFAdd(fn.cur.NewFMul(a, d), fn.cur.NewFMul(b, c))
+	addMetadata(imag, "comment", "imag")
+	return fn.emitComplex(typ, fn.emitFloatTrunc(real, typ), fn.emitFloatTrunc(imag, typ))
+}
+
+// --- [ complex division ] ----------------------------------------------------
+
+// emitComplexDiv emits the complex quotient x/y, emitting to fn.
+//
+// Complex division is performed by runtime.complex128div, which handles
+// infinities and NaN the same way as gc; complex64 values are converted to
+// complex128 and back, as done by gc.
+func (fn *Func) emitComplexDiv(x, y irvalue.Value) irValueInstruction {
+	typ := x.Type().(*irtypes.StructType)
+	complex128Type := fn.m.irTypeFromName("complex128").(*irtypes.StructType)
+	n := fn.emitComplexConvert(x, complex128Type)
+	m := fn.emitComplexConvert(y, complex128Type)
+	complex128div := fn.m.getPredeclaredFunc("runtime.complex128div")
+	result := fn.cur.NewCall(complex128div, n, m)
+	return fn.emitComplexConvert(result, typ)
+}
+
+// --- [ complex equality ] ----------------------------------------------------
+
+// emitComplexEqual emits the equality comparison x == y of complex values,
+// emitting to fn. Complex values are equal if both their real and imaginary
+// parts are equal.
+func (fn *Func) emitComplexEqual(x, y irvalue.Value) irValueInstruction {
+	xReal, xImag := fn.emitRealImagParts(x)
+	yReal, yImag := fn.emitRealImagParts(y)
+	realEqual := fn.cur.NewFCmp(irenum.FPredOEQ, xReal, yReal)
+	imagEqual := fn.cur.NewFCmp(irenum.FPredOEQ, xImag, yImag)
+	return fn.cur.NewAnd(realEqual, imagEqual)
+}
+
+// --- [ complex negation ] ----------------------------------------------------
+
+// emitComplexNeg emits the complex negation -x, emitting to fn.
+func (fn *Func) emitComplexNeg(x irvalue.Value) irValueInstruction {
+	typ := x.Type().(*irtypes.StructType)
+	real, imag := fn.emitRealImagParts(x)
+	return fn.emitComplex(typ, fn.cur.NewFNeg(real), fn.cur.NewFNeg(imag))
+}
+
+// --- [ complex conversion ] --------------------------------------------------
+
+// emitComplexConvert converts the given complex value to the specified complex
+// type, emitting to fn. Both parts are converted separately.
+func (fn *Func) emitComplexConvert(x irvalue.Value, to *irtypes.StructType) irValueInstruction {
+	real, imag := fn.emitRealImagParts(x)
+	fieldType := to.Fields[0].(*irtypes.FloatType)
+	fromType := real.Type().(*irtypes.FloatType)
+	fromPrec := precFromFloatKind(fromType.Kind)
+	toPrec := precFromFloatKind(fieldType.Kind)
+	switch {
+	case fromPrec < toPrec:
+		real = fn.cur.NewFPExt(real, fieldType)
+		imag = fn.cur.NewFPExt(imag, fieldType)
+	case fromPrec > toPrec:
+		real = fn.cur.NewFPTrunc(real, fieldType)
+		imag = fn.cur.NewFPTrunc(imag, fieldType)
+	}
+	return fn.emitComplex(to, real, imag)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// emitComplex returns a complex value of the given complex type with the
+// specified real and imaginary parts, emitting to fn.
+func (fn *Func) emitComplex(typ *irtypes.StructType, real, imag irvalue.Value) irValueInstruction {
+	zero := irconstant.NewZeroInitializer(typ)
+	withReal := fn.cur.NewInsertValue(zero, real, 0)
+	addMetadata(withReal, "comment", "real")
+	inst := fn.cur.NewInsertValue(withReal, imag, 1)
+	addMetadata(inst, "comment", "imag")
+	return inst
+}
+
+// emitRealImagParts returns the real and imaginary parts of the given complex
+// value, emitting to fn.
+func (fn *Func) emitRealImagParts(x irvalue.Value) (real, imag irvalue.Value) {
+	realPart := fn.cur.NewExtractValue(x, 0)
+	addMetadata(realPart, "comment", "real")
+	imagPart := fn.cur.NewExtractValue(x, 1)
+	addMetadata(imagPart, "comment", "imag")
+	return realPart, imagPart
+}
+
+// emitFloatExt extends the given floating-point value to double, emitting to
+// fn.
+func (fn *Func) emitFloatExt(x irvalue.Value) irvalue.Value {
+	if x.Type().(*irtypes.FloatType).Kind == irtypes.FloatKindDouble {
+		return x
+	}
+	return fn.cur.NewFPExt(x, irtypes.Double)
+}
+
+// emitFloatTrunc truncates the given double value to the part type of the
+// specified complex type, emitting to fn.
+func (fn *Func) emitFloatTrunc(x irvalue.Value, typ *irtypes.StructType) irvalue.Value {
+	fieldType := typ.Fields[0].(*irtypes.FloatType)
+	if fieldType.Kind == irtypes.FloatKindDouble {
+		return x
+	}
+	return fn.cur.NewFPTrunc(x, fieldType)
+}
@@ -0,0 +1,477 @@
+package irgen
+
+import (
+	"fmt"
+	gotypes "go/types"
+
+	"github.com/llir/llvm/ir"
+	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
+	irtypes "github.com/llir/llvm/ir/types"
+	irvalue "github.com/llir/llvm/ir/value"
+	"golang.org/x/tools/go/ssa"
+)
+
+// Deferred calls are recorded in a linked list of defer records (the defer
+// stack of the runtime), most recently deferred first, and are run (and popped)
+// by the deferring function before returning, or by the runtime on panics.
+//
+// As done by gc, the deferred calls of functions with at most 8 defer
+// statements, none of which are in loops, are open-coded. Their defer records
+// are allocated on the stack and pushed inline, and they are run by calling the
+// deferred calls directly on return, rather than through the runtime.
+// Otherwise, defer records are allocated on the heap by runtime.deferproc and
+// run by runtime.deferreturn.
+//
+// Each deferred call is performed by a synthesized thunk function, which takes
+// as argument the environment of the deferred call; a structure holding the
+// function-local values used by the call (e.g. the function value and
+// arguments), as evaluated at the defer statement.
+//
+// Panics are recovered using setjmp and longjmp. After each defer statement, the
+// deferring function saves its execution context in a jump buffer, which is
+// recorded in the defer records of the function. When a deferred call run by a
+// panic recovers the panic, the runtime performs a long jump to the jump buffer
+// of the deferring function, which runs its remaining deferred calls and
+// returns normally, with the current values of its named results.
+//
+// Before each deferred call run by a panic, the runtime records the panic as
+// recoverable. The deferred function takes (i.e. loads and clears) the
+// recoverable panic on function entry, thus only calls to recover made directly
+// by the deferred function may recover the panic; as required by the Go
+// specification, recover returns nil in the functions it calls.
+
+// jmpbufLen is the length in 64-bit words of jump buffers, large enough to hold
+// a jmp_buf of the C library on supported platforms.
+const jmpbufLen = 32
+
+// maxOpenDefers is the maximum number of defer statements of functions with
+// open-coded deferred calls.
+const maxOpenDefers = 8
+
+// maxOpenDeferExits is the maximum number of defer statements times the number
+// of function exits of functions with open-coded deferred calls, to limit code
+// size.
+const maxOpenDeferExits = 15
+
+// deferFrame holds the deferred calls of a function.
+type deferFrame struct {
+	// Deferred calls of the function, in order of appearance.
+	sites []*deferSite
+	// Map from Go SSA defer instruction to deferred call.
+	siteOf map[*ssa.Defer]*deferSite
+	// Specifies whether the deferred calls of the function are open-coded.
+	openCoded bool
+	// Top of the defer stack on function entry; the deferred calls above base
+	// are run on return. Only used if the deferred calls are not open-coded.
+	base irvalue.Value
+	// Jump buffer (as unsafe pointer) holding the execution context of the
+	// function, saved after each defer statement.
+	jmpbuf irvalue.Value
+	// Basic block resuming execution after a deferred call of the function has
+	// recovered a panic.
+	recoverBlock *ir.Block
+}
+
+// deferSite is a deferred call of a defer statement.
+type deferSite struct {
+	// Go SSA defer instruction.
+	goInst *ssa.Defer
+	// Function-local Go SSA values used by the deferred call, which are stored
+	// in the environment of the deferred call.
+	goEnvValues []ssa.Value
+	// Go type of the environment of the deferred call.
+	goEnvType *gotypes.Struct
+	// Thunk function performing the deferred call.
+	//
+	//    func f$deferN(env unsafe.Pointer)
+	thunk *ir.Func
+	// Stack-allocated defer record and environment (as unsafe pointer) of the
+	// deferred call. Only used if the deferred calls are open-coded.
+	rec, env irvalue.Value
+}
+
+// --- [ init ] ----------------------------------------------------------------
+
+// initDefers prepares the deferred calls of fn, emitting to the entry basic
+// block of fn.
+func (fn *Func) initDefers() {
+	var goDefers []*ssa.Defer
+	nexits := 0
+	for _, goBlock := range fn.goFunc.Blocks {
+		for _, goInst := range goBlock.Instrs {
+			switch goInst := goInst.(type) {
+			case *ssa.Defer:
+				goDefers = append(goDefers, goInst)
+			case *ssa.RunDefers:
+				nexits++
+			}
+		}
+	}
+	if len(goDefers) == 0 {
+		return
+	}
+	dbg.Println("initDefers")
+	frame := &deferFrame{
+		siteOf:    make(map[*ssa.Defer]*deferSite),
+		openCoded: len(goDefers) <= maxOpenDefers && len(goDefers)*nexits <= maxOpenDeferExits,
+	}
+	for _, goDefer := range goDefers {
+		if inLoop(goDefer.Block()) {
+			frame.openCoded = false
+		}
+	}
+	jmpbuf := fn.entry.NewAlloca(irtypes.NewArray(jmpbufLen, irtypes.I64))
+	jmpbuf.SetName("defer.jmpbuf")
+	frame.jmpbuf = fn.entry.NewBitCast(jmpbuf, irtypes.I8Ptr)
+	deferType := fn.m.irTypeFromName("runtime._defer")
+	for i, goDefer := range goDefers {
+		site := &deferSite{goInst: goDefer}
+		site.goEnvValues, site.goEnvType = deferEnv(&goDefer.Call)
+		site.thunk = fn.synthDeferThunk(site, i)
+		if frame.openCoded {
+			// Allocate defer record and environment on the stack, and initialize
+			// the fields of the defer record that are known on function entry.
+			rec := fn.entry.NewAlloca(deferType)
+			rec.SetName(fmt.Sprintf("defer%d", i))
+			site.rec = rec
+			site.env = irconstant.NewNull(irtypes.I8Ptr)
+			if len(site.goEnvValues) > 0 {
+				env := fn.entry.NewAlloca(fn.m.irTypeFromGo(site.goEnvType))
+				env.SetName(fmt.Sprintf("defer%d.env", i))
+				site.env = fn.entry.NewBitCast(env, irtypes.I8Ptr)
+			}
+			fn.entry.NewStore(site.thunk, fn.emitDeferField(fn.entry, rec, 1))
+			fn.entry.NewStore(site.env, fn.emitDeferField(fn.entry, rec, 2))
+			fn.entry.NewStore(frame.jmpbuf, fn.emitDeferField(fn.entry, rec, 3))
+		}
+		frame.sites = append(frame.sites, site)
+		frame.siteOf[goDefer] = site
+	}
+	if !frame.openCoded {
+		deferStack := fn.m.getDeferStack()
+		base := fn.entry.NewLoad(deferStack.ContentType, deferStack)
+		base.SetName("defer.base")
+		frame.base = base
+	}
+	fn.defers = frame
+	fn.initRecover()
+}
+
+// initRecover emits the basic block of fn resuming execution after a deferred
+// call of fn has recovered a panic. The remaining deferred calls of fn are run,
+// before branching to the recover block of the Go SSA function, which returns
+// the current values of the named results.
+func (fn *Func) initRecover() {
+	dbg.Println("initRecover")
+	cur := fn.cur
+	fn.cur = fn.newAuxBlock("defer.recover")
+	fn.defers.recoverBlock = fn.cur
+	// The long jump skips the runtime restoring the recoverable panic.
+	recoverPanic := fn.m.getRecoverPanic()
+	fn.cur.NewStore(irconstant.NewNull(recoverPanic.ContentType.(*irtypes.PointerType)), recoverPanic)
+	fn.emitPendingDefers()
+	fn.cur.NewBr(fn.getBlock(fn.goFunc.Recover))
+	fn.cur = cur
+}
+
+// initRecoverPanic takes the panic recoverable by fn, emitting to the entry
+// basic block of fn.
+//
+// The recoverable panic is cleared on entry of every function containing calls,
+// so that it is never visible to the functions called by a deferred function.
+// Synthetic wrapper functions (e.g. bound method wrappers) leave it to the
+// function they wrap.
+func (fn *Func) initRecoverPanic() {
+	if len(fn.goFunc.Synthetic) > 0 {
+		return
+	}
+	hasCalls, hasRecover := false, false
+	for _, goBlock := range fn.goFunc.Blocks {
+		for _, goInst := range goBlock.Instrs {
+			goCallInst, ok := goInst.(ssa.CallInstruction)
+			if !ok {
+				continue
+			}
+			hasCalls = true
+			if goBuiltin, ok := goCallInst.Common().Value.(*ssa.Builtin); ok && goBuiltin.Name() == "recover" {
+				hasRecover = true
+			}
+		}
+	}
+	if !hasCalls {
+		return
+	}
+	recoverPanic := fn.m.getRecoverPanic()
+	if hasRecover {
+		p := fn.entry.NewLoad(recoverPanic.ContentType, recoverPanic)
+		p.SetName("recoverpanic")
+		fn.recoverPanic = p
+	}
+	fn.entry.NewStore(irconstant.NewNull(recoverPanic.ContentType.(*irtypes.PointerType)), recoverPanic)
+}
+
+// useRecoverPanic returns the panic recoverable by calls to recover in fn. A nil
+// pointer is returned for functions not taking a recoverable panic (e.g. the
+// thunk of `defer recover()`, as recover is then not called by the deferred
+// function but by the runtime).
+func (fn *Func) useRecoverPanic() irvalue.Value {
+	if fn.recoverPanic == nil {
+		panicPtrType := irtypes.NewPointer(fn.m.irTypeFromName("runtime._panic"))
+		return irconstant.NewNull(panicPtrType)
+	}
+	return fn.recoverPanic
+}
+
+// --- [ defer instruction ] ---------------------------------------------------
+
+// emitDefer compiles the given Go SSA defer instruction to corresponding LLVM
+// IR instructions, emitting to fn.
+//
+// The function-local values used by the deferred call are evaluated and stored
+// in the environment of the deferred call, and a defer record of the deferred
+// call is pushed to the defer stack.
+func (fn *Func) emitDefer(goInst *ssa.Defer) error {
+	dbg.Println("emitDefer")
+	site := fn.defers.siteOf[goInst]
+	if fn.defers.openCoded {
+		fn.emitDeferEnv(site, site.env)
+		// Push defer record to the defer stack.
+		deferStack := fn.m.getDeferStack()
+		top := fn.cur.NewLoad(deferStack.ContentType, deferStack)
+		fn.cur.NewStore(top, fn.emitDeferField(fn.cur, site.rec, 0))
+		fn.cur.NewStore(site.rec, deferStack)
+	} else {
+		var env irvalue.Value = irconstant.NewNull(irtypes.I8Ptr)
+		if len(site.goEnvValues) > 0 {
+			newFunc := fn.m.synthNew(site.goEnvType)
+			env = fn.cur.NewBitCast(fn.cur.NewCall(newFunc), irtypes.I8Ptr)
+			fn.emitDeferEnv(site, env)
+		}
+		deferproc := fn.m.getPredeclaredFunc("runtime.deferproc")
+		fn.cur.NewCall(deferproc, site.thunk, env, fn.defers.jmpbuf)
+	}
+	fn.emitSetjmp()
+	return nil
+}
+
+// emitSetjmp saves the execution context of fn in its jump buffer, emitting to
+// fn. Execution resumes at the recover basic block of fn on long jumps to the
+// jump buffer.
+func (fn *Func) emitSetjmp() {
+	setjmp := fn.m.getPredeclaredFunc("_setjmp")
+	result := fn.cur.NewCall(setjmp, fn.defers.jmpbuf)
+	recovered := fn.cur.NewICmp(irenum.IPredNE, result, irconstant.NewInt(irtypes.I32, 0))
+	contBlock := fn.newAuxBlock("defer.cont")
+	fn.cur.NewCondBr(recovered, fn.defers.recoverBlock, contBlock)
+	fn.cur = contBlock
+}
+
+// emitDeferEnv evaluates the function-local values used by the given deferred
+// call and stores them in the environment env (as unsafe pointer), emitting to
+// fn.
+func (fn *Func) emitDeferEnv(site *deferSite, env irvalue.Value) {
+	if len(site.goEnvValues) == 0 {
+		return
+	}
+	envType := fn.m.irTypeFromGo(site.goEnvType)
+	envPtr := fn.cur.NewBitCast(env, irtypes.NewPointer(envType))
+	zero := irconstant.NewInt(irtypes.I64, 0)
+	for i, goValue := range site.goEnvValues {
+		indices := []irvalue.Value{
+			zero,
+			irconstant.NewInt(irtypes.I32, int64(i)),
+		}
+		ptr := fn.cur.NewGetElementPtr(envType, envPtr, indices...)
+		fn.cur.NewStore(fn.useValue(goValue), ptr)
+	}
+}
+
+// --- [ rundefers instruction ] -----------------------------------------------
+
+// emitRunDefers compiles the given Go SSA rundefers instruction to
+// corresponding LLVM IR instructions, emitting to fn.
+func (fn *Func) emitRunDefers(goInst *ssa.RunDefers) error {
+	dbg.Println("emitRunDefers")
+	if fn.defers == nil {
+		// Go SSA emits rundefers instructions on return also in functions
+		// without defer statements (unless lifted to SSA form).
+		return nil
+	}
+	fn.emitPendingDefers()
+	return nil
+}
+
+// emitPendingDefers runs the pending deferred calls of fn, emitting to fn.
+// Deferred calls run by fn cannot recover panics.
+//
+// Open-coded deferred calls are run in reverse order of the defer statements,
+// by calling the thunk of each deferred call whose defer record is on top of
+// the defer stack; thus only deferred calls whose defer statement has been
+// executed, and which have not yet been run (e.g. by a panic), are run.
+func (fn *Func) emitPendingDefers() {
+	if !fn.defers.openCoded {
+		deferreturn := fn.m.getPredeclaredFunc("runtime.deferreturn")
+		fn.cur.NewCall(deferreturn, fn.defers.base)
+		return
+	}
+	deferStack := fn.m.getDeferStack()
+	deferType := fn.m.irTypeFromName("runtime._defer").(*irtypes.StructType)
+	for i := len(fn.defers.sites) - 1; i >= 0; i-- {
+		site := fn.defers.sites[i]
+		top := fn.cur.NewLoad(deferStack.ContentType, deferStack)
+		pending := fn.cur.NewICmp(irenum.IPredEQ, top, site.rec)
+		callBlock := fn.newAuxBlock("defer.call")
+		doneBlock := fn.newAuxBlock("defer.done")
+		fn.cur.NewCondBr(pending, callBlock, doneBlock)
+		// Pop defer record before running the deferred call.
+		link := callBlock.NewLoad(deferType.Fields[0], fn.emitDeferField(callBlock, site.rec, 0))
+		callBlock.NewStore(link, deferStack)
+		callBlock.NewCall(site.thunk, site.env)
+		callBlock.NewBr(doneBlock)
+		fn.cur = doneBlock
+	}
+}
+
+// --- [ thunk ] ---------------------------------------------------------------
+
+// synthDeferThunk synthesizes the thunk function of the given deferred call of
+// fn, the i:th deferred call of fn. The thunk loads the function-local values
+// used by the deferred call from its environment and performs the call.
+//
+//	func f$deferN(env unsafe.Pointer)
+func (fn *Func) synthDeferThunk(site *deferSite, i int) *ir.Func {
+	dbg.Println("synthDeferThunk")
+	thunkName := fmt.Sprintf("%s$defer%d", fn.Func.Name(), i)
+	env := ir.NewParam("env", irtypes.I8Ptr)
+	f := fn.m.Module.NewFunc(thunkName, irtypes.Void, env)
+	// Thunks are only referred to by the deferring function.
+	f.Linkage = irenum.LinkageInternal
+	entry := f.NewBlock("entry")
+	thunk := &Func{
+		Func:   f,
+		goFunc: fn.goFunc,
+		m:      fn.m,
+		entry:  entry,
+		cur:    entry,
+		locals: make(map[ssa.Value]irvalue.Value),
+		blocks: make(map[*ssa.BasicBlock]*ir.Block),
+		exits:  make(map[*ssa.BasicBlock]*ir.Block),
+	}
+	if len(site.goEnvValues) > 0 {
+		envType := fn.m.irTypeFromGo(site.goEnvType)
+		envPtr := entry.NewBitCast(env, irtypes.NewPointer(envType))
+		zero := irconstant.NewInt(irtypes.I64, 0)
+		for i, goValue := range site.goEnvValues {
+			indices := []irvalue.Value{
+				zero,
+				irconstant.NewInt(irtypes.I32, int64(i)),
+			}
+			ptr := entry.NewGetElementPtr(envType, envPtr, indices...)
+			v := entry.NewLoad(envType.(*irtypes.StructType).Fields[i], ptr)
+			thunk.setLocal(goValue, v)
+		}
+	}
+	// Perform the deferred call as a call instruction (with results, if any,
+	// discarded).
+	goCall := &ssa.Call{Call: site.goInst.Call}
+	if err := thunk.emitCall(goCall); err != nil {
+		panic(fmt.Errorf("unable to synthesize thunk of deferred call in %q; %v", fn.m.fullName(fn.goFunc), err))
+	}
+	thunk.cur.NewRet(nil)
+	return f
+}
+
+// ### [ Helper functions ] ####################################################
+
+// getDeferStack returns the LLVM IR global variable holding the top of the
+// defer stack of the runtime, declaring it if not present.
+//
+//	var runtime.defers *_defer
+func (m *Module) getDeferStack() *ir.Global {
+	if m.deferStack == nil {
+		deferPtrType := irtypes.NewPointer(m.irTypeFromName("runtime._defer"))
+		m.deferStack = m.Module.NewGlobal("runtime.defers", deferPtrType)
+		// Defined by the runtime.
+		m.deferStack.Linkage = irenum.LinkageExternal
+	}
+	return m.deferStack
+}
+
+// getRecoverPanic returns the LLVM IR global variable holding the panic
+// recoverable by the deferred function being called by the runtime, declaring
+// it if not present.
+//
+//	var runtime.recoverpanic *_panic
+func (m *Module) getRecoverPanic() *ir.Global {
+	if m.recoverPanic == nil {
+		panicPtrType := irtypes.NewPointer(m.irTypeFromName("runtime._panic"))
+		m.recoverPanic = m.Module.NewGlobal("runtime.recoverpanic", panicPtrType)
+		// Defined by the runtime.
+		m.recoverPanic.Linkage = irenum.LinkageExternal
+	}
+	return m.recoverPanic
+}
+
+// emitDeferField returns a pointer to the field with the given index of the
+// defer record rec, emitting to block.
+func (fn *Func) emitDeferField(block *ir.Block, rec irvalue.Value, index int64) irValueInstruction {
+	deferType := fn.m.irTypeFromName("runtime._defer")
+	indices := []irvalue.Value{
+		irconstant.NewInt(irtypes.I64, 0),
+		irconstant.NewInt(irtypes.I32, index),
+	}
+	ptr := block.NewGetElementPtr(deferType, rec, indices...)
+	addMetadata(ptr, "field", deferFieldNames[index])
+	return ptr
+}
+
+// deferFieldNames maps from field index to field name of defer records.
+var deferFieldNames = []string{"link", "fn", "arg", "frame"}
+
+// deferEnv returns the function-local Go SSA values used by the given deferred
+// call, and the Go type of the environment holding them.
+func deferEnv(goCall *ssa.CallCommon) ([]ssa.Value, *gotypes.Struct) {
+	var goValues []ssa.Value
+	seen := make(map[ssa.Value]bool)
+	operands := append([]ssa.Value{goCall.Value}, goCall.Args...)
+	for _, goValue := range operands {
+		switch goValue.(type) {
+		case *ssa.Builtin, *ssa.Const, *ssa.Function, *ssa.Global:
+			// Module-level values.
+			continue
+		}
+		if seen[goValue] {
+			continue
+		}
+		seen[goValue] = true
+		goValues = append(goValues, goValue)
+	}
+	var goFields []*gotypes.Var
+	for i, goValue := range goValues {
+		fieldName := fmt.Sprintf("v%d", i)
+		goField := gotypes.NewField(goValue.Pos(), nil, fieldName, goValue.Type(), false)
+		goFields = append(goFields, goField)
+	}
+	return goValues, gotypes.NewStruct(goFields, nil)
+}
+
+// inLoop reports whether the given basic block is part of a loop; i.e. whether
+// the basic block is reachable from itself.
+func inLoop(goBlock *ssa.BasicBlock) bool {
+	visited := make(map[*ssa.BasicBlock]bool)
+	queue := append([]*ssa.BasicBlock{}, goBlock.Succs...)
+	for len(queue) > 0 {
+		b := queue[0]
+		queue = queue[1:]
+		if b == goBlock {
+			return true
+		}
+		if visited[b] {
+			continue
+		}
+		visited[b] = true
+		queue = append(queue, b.Succs...)
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/llir/llvm/ir"
+	irenum "github.com/llir/llvm/ir/enum"
 	irtypes "github.com/llir/llvm/ir/types"
 	irvalue "github.com/llir/llvm/ir/value"
 	"github.com/pkg/errors"
@@ -31,6 +32,41 @@ type Func struct {
 	// Maps from Go SSA basic block to corresponding LLVM IR basic block in the
 	// LLVM IR function being generated.
 	blocks map[*ssa.BasicBlock]*ir.Block
+	// Maps from Go SSA basic block to the last LLVM IR basic block generated
+	// for the Go SSA basic block; which differs from the corresponding LLVM IR
+	// basic block when instructions are lowered to control flow (e.g. runtime
+	// checks).
+	exits map[*ssa.BasicBlock]*ir.Block
+	// Number of auxiliary LLVM IR basic blocks created in the function, used to
+	// generate unique basic block names.
+	nauxBlocks int
+	// Deferred calls of the function; nil if the function contains no defer
+	// statements.
+	defers *deferFrame
+	// Panic recoverable by calls to recover in the function, as taken on
+	// function entry; nil if the function does not call recover.
+	recoverPanic irvalue.Value
+}
+
+// setLocal records the LLVM IR value corresponding to the given function local
+// Go SSA value.
+//
+// Pointer values loaded from recursive types may be of placeholder type (see
+// irTypeFromGo), and are converted to the pointer type of their Go type.
+func (fn *Func) setLocal(goValue ssa.Value, v irvalue.Value) {
+	// Note, the Go type of range instructions is opaque, and the call
+	// instructions performing deferred calls in thunks are untyped (see
+	// synthDeferThunk).
+	goType := goValue.Type()
+	if _, ok := goValue.(*ssa.Range); !ok && goType != nil && isPointerType(goType) {
+		if _, ok := v.Type().(*irtypes.PointerType); ok {
+			typ := fn.m.irTypeFromGo(goType)
+			if v.Type().LLString() != typ.LLString() {
+				v = fn.cur.NewBitCast(v, typ)
+			}
+		}
+	}
+	fn.locals[goValue] = v
 }
 
 // NewFunc returns a new LLVM IR function generator for the given Go SSA
@@ -48,6 +84,7 @@ func (m *Module) NewFunc(goFunc *ssa.Function) *Func {
 		cur:    entry,
 		locals: make(map[ssa.Value]irvalue.Value),
 		blocks: make(map[*ssa.BasicBlock]*ir.Block),
+		exits:  make(map[*ssa.BasicBlock]*ir.Block),
 	}
 }
 
@@ -136,6 +173,537 @@ func (m *Module) initPredeclaredFuncs() {
 		stringCmpFunc := m.Module.NewFunc("cmp.string", retType, x, y)
 		m.predeclaredFuncs[stringCmpFunc.Name()] = stringCmpFunc
 	}
+
+	// --- [ interfaces ] ---
+
+	// runtime.ifaceeq
+	//
+	// ifaceeq reports whether the interface values x and y are equal.
+	//
+	//    func runtime.ifaceeq(xTab *itab, xData unsafe.Pointer, yTab *itab, yData unsafe.Pointer) bool
+	{
+		retType := m.irTypeFromName("bool")
+		itabPtrType := irtypes.NewPointer(m.irTypeFromName("runtime.itab"))
+		params := []*ir.Param{
+			ir.NewParam("x_tab", itabPtrType),
+			ir.NewParam("x_data", irtypes.I8Ptr),
+			ir.NewParam("y_tab", itabPtrType),
+			ir.NewParam("y_data", irtypes.I8Ptr),
+		}
+		ifaceeqFunc := m.Module.NewFunc("runtime.ifaceeq", retType, params...)
+		m.predeclaredFuncs[ifaceeqFunc.Name()] = ifaceeqFunc
+	}
+
+	// runtime.typeassert
+	//
+	// typeassert reports whether the interface value with interface method table
+	// tab (of static type iface) holds the concrete type want. If canfail is
+	// false, a failed type assertion panics.
+	//
+	//    func runtime.typeassert(tab *itab, want, iface *_type, canfail bool) bool
+	{
+		retType := m.irTypeFromName("bool")
+		itabPtrType := irtypes.NewPointer(m.irTypeFromName("runtime.itab"))
+		typePtrType := irtypes.NewPointer(m.irTypeFromName("runtime._type"))
+		params := []*ir.Param{
+			ir.NewParam("tab", itabPtrType),
+			ir.NewParam("want", typePtrType),
+			ir.NewParam("iface", typePtrType),
+			ir.NewParam("canfail", m.irTypeFromName("bool")),
+		}
+		typeassertFunc := m.Module.NewFunc("runtime.typeassert", retType, params...)
+		m.predeclaredFuncs[typeassertFunc.Name()] = typeassertFunc
+	}
+
+	// runtime.getitab
+	//
+	// getitab returns the interface method table of the interface type inter for
+	// the dynamic type of the interface value with interface method table tab. If
+	// the dynamic type does not implement inter (or the interface value is nil),
+	// getitab returns nil if canfail is true, and panics otherwise.
+	//
+	//    func runtime.getitab(inter *_type, tab *itab, canfail bool) *itab
+	{
+		itabPtrType := irtypes.NewPointer(m.irTypeFromName("runtime.itab"))
+		typePtrType := irtypes.NewPointer(m.irTypeFromName("runtime._type"))
+		retType := itabPtrType
+		params := []*ir.Param{
+			ir.NewParam("inter", typePtrType),
+			ir.NewParam("tab", itabPtrType),
+			ir.NewParam("canfail", m.irTypeFromName("bool")),
+		}
+		getitabFunc := m.Module.NewFunc("runtime.getitab", retType, params...)
+		m.predeclaredFuncs[getitabFunc.Name()] = getitabFunc
+	}
+
+	// --- [ strings ] ---
+
+	// runtime.decoderune
+	//
+	// decoderune returns the rune at byte offset k of s and the byte offset of
+	// the next rune. Invalid UTF-8 sequences are decoded as U+FFFD of width 1.
+	//
+	//    func runtime.decoderune(s string, k int) (r rune, pos int)
+	{
+		retType := irtypes.NewStruct(m.irTypeFromName("int32"), m.irTypeFromName("int"))
+		params := []*ir.Param{
+			ir.NewParam("s", m.irTypeFromName("string")),
+			ir.NewParam("k", m.irTypeFromName("int")),
+		}
+		decoderuneFunc := m.Module.NewFunc("runtime.decoderune", retType, params...)
+		m.predeclaredFuncs[decoderuneFunc.Name()] = decoderuneFunc
+	}
+
+	// runtime.intstring
+	//
+	// intstring returns the UTF-8 encoding of the code point v.
+	//
+	//    func runtime.intstring(v int64) string
+	{
+		retType := m.irTypeFromName("string")
+		param := ir.NewParam("v", m.irTypeFromName("int64"))
+		intstringFunc := m.Module.NewFunc("runtime.intstring", retType, param)
+		m.predeclaredFuncs[intstringFunc.Name()] = intstringFunc
+	}
+
+	// runtime.stringtoslicebyte
+	//
+	// stringtoslicebyte returns a new byte slice holding a copy of the contents
+	// of s.
+	//
+	//    func runtime.stringtoslicebyte(s string) []byte
+	{
+		retType := m.newSliceType(m.irTypeFromName("uint8"))
+		param := ir.NewParam("s", m.irTypeFromName("string"))
+		stringtoslicebyteFunc := m.Module.NewFunc("runtime.stringtoslicebyte", retType, param)
+		m.predeclaredFuncs[stringtoslicebyteFunc.Name()] = stringtoslicebyteFunc
+	}
+
+	// runtime.slicebytetostring
+	//
+	// slicebytetostring returns a new string holding a copy of the contents of
+	// b.
+	//
+	//    func runtime.slicebytetostring(b []byte) string
+	{
+		retType := m.irTypeFromName("string")
+		param := ir.NewParam("b", m.newSliceType(m.irTypeFromName("uint8")))
+		slicebytetostringFunc := m.Module.NewFunc("runtime.slicebytetostring", retType, param)
+		m.predeclaredFuncs[slicebytetostringFunc.Name()] = slicebytetostringFunc
+	}
+
+	// runtime.stringtoslicerune
+	//
+	// stringtoslicerune returns a new rune slice holding the runes of s.
+	//
+	//    func runtime.stringtoslicerune(s string) []rune
+	{
+		retType := m.newSliceType(m.irTypeFromName("int32"))
+		param := ir.NewParam("s", m.irTypeFromName("string"))
+		stringtosliceruneFunc := m.Module.NewFunc("runtime.stringtoslicerune", retType, param)
+		m.predeclaredFuncs[stringtosliceruneFunc.Name()] = stringtosliceruneFunc
+	}
+
+	// runtime.slicerunetostring
+	//
+	// slicerunetostring returns a new string holding the UTF-8 encoding of the
+	// runes of r.
+	//
+	//    func runtime.slicerunetostring(r []rune) string
+	{
+		retType := m.irTypeFromName("string")
+		param := ir.NewParam("r", m.newSliceType(m.irTypeFromName("int32")))
+		slicerunetostringFunc := m.Module.NewFunc("runtime.slicerunetostring", retType, param)
+		m.predeclaredFuncs[slicerunetostringFunc.Name()] = slicerunetostringFunc
+	}
+
+	// runtime.strequal
+	//
+	// strequal reports whether x and y are equal.
+	//
+	//    func runtime.strequal(x, y string) bool
+	{
+		retType := m.irTypeFromName("bool")
+		params := []*ir.Param{
+			ir.NewParam("x", m.irTypeFromName("string")),
+			ir.NewParam("y", m.irTypeFromName("string")),
+		}
+		strequalFunc := m.Module.NewFunc("runtime.strequal", retType, params...)
+		m.predeclaredFuncs[strequalFunc.Name()] = strequalFunc
+	}
+
+	// runtime.concatstrings
+	//
+	// concatstrings returns the concatenation of the strings of a.
+	//
+	//    func runtime.concatstrings(a []string) string
+	{
+		retType := m.irTypeFromName("string")
+		param := ir.NewParam("a", m.newSliceType(m.irTypeFromName("string")))
+		concatstringsFunc := m.Module.NewFunc("runtime.concatstrings", retType, param)
+		m.predeclaredFuncs[concatstringsFunc.Name()] = concatstringsFunc
+	}
+
+	// --- [ slices ] ---
+
+	// runtime.makeslice
+	//
+	// makeslice allocates the zero-initialized backing array of a slice with the
+	// given length and capacity, and element size in bytes.
+	//
+	//    func runtime.makeslice(size, len, cap int) unsafe.Pointer
+	{
+		retType := irtypes.I8Ptr
+		params := []*ir.Param{
+			ir.NewParam("size", m.irTypeFromName("int")),
+			ir.NewParam("len", m.irTypeFromName("int")),
+			ir.NewParam("cap", m.irTypeFromName("int")),
+		}
+		makesliceFunc := m.Module.NewFunc("runtime.makeslice", retType, params...)
+		m.predeclaredFuncs[makesliceFunc.Name()] = makesliceFunc
+	}
+
+	// runtime.appendslice
+	//
+	// appendslice appends the n elements at p to s, and returns the resulting
+	// slice.
+	//
+	//    func runtime.appendslice(size int, s slice, p unsafe.Pointer, n int) slice
+	{
+		sliceType := m.irTypeFromName("runtime.slice")
+		retType := sliceType
+		params := []*ir.Param{
+			ir.NewParam("size", m.irTypeFromName("int")),
+			ir.NewParam("s", sliceType),
+			ir.NewParam("p", irtypes.I8Ptr),
+			ir.NewParam("n", m.irTypeFromName("int")),
+		}
+		appendsliceFunc := m.Module.NewFunc("runtime.appendslice", retType, params...)
+		m.predeclaredFuncs[appendsliceFunc.Name()] = appendsliceFunc
+	}
+
+	// runtime.slicecopy
+	//
+	// slicecopy copies min(dstlen, srclen) elements of the given size in bytes
+	// from src to dst, and returns the number of elements copied.
+	//
+	//    func runtime.slicecopy(dst unsafe.Pointer, dstlen int, src unsafe.Pointer, srclen int, size int) int
+	{
+		retType := m.irTypeFromName("int")
+		params := []*ir.Param{
+			ir.NewParam("dst", irtypes.I8Ptr),
+			ir.NewParam("dstlen", m.irTypeFromName("int")),
+			ir.NewParam("src", irtypes.I8Ptr),
+			ir.NewParam("srclen", m.irTypeFromName("int")),
+			ir.NewParam("size", m.irTypeFromName("int")),
+		}
+		slicecopyFunc := m.Module.NewFunc("runtime.slicecopy", retType, params...)
+		m.predeclaredFuncs[slicecopyFunc.Name()] = slicecopyFunc
+	}
+
+	// --- [ runtime panics ] ---
+
+	// runtime.gopanic
+	//
+	// gopanic implements the predeclared function panic. It panics with the
+	// empty interface value with interface method table tab and data pointer
+	// data.
+	//
+	//    func runtime.gopanic(tab *itab, data unsafe.Pointer)
+	{
+		retType := irtypes.Void
+		params := []*ir.Param{
+			ir.NewParam("tab", irtypes.NewPointer(m.irTypeFromName("runtime.itab"))),
+			ir.NewParam("data", irtypes.I8Ptr),
+		}
+		gopanicFunc := m.Module.NewFunc("runtime.gopanic", retType, params...)
+		m.predeclaredFuncs[gopanicFunc.Name()] = gopanicFunc
+	}
+
+	// runtime.panicerror
+	//
+	// panicerror panics with a runtime error with the given error message.
+	//
+	//    func runtime.panicerror(msg string)
+	{
+		retType := irtypes.Void
+		param := ir.NewParam("msg", m.irTypeFromName("string"))
+		panicerrorFunc := m.Module.NewFunc("runtime.panicerror", retType, param)
+		m.predeclaredFuncs[panicerrorFunc.Name()] = panicerrorFunc
+	}
+
+	// runtime.gorecover
+	//
+	// gorecover implements the predeclared function recover. It stops the panic
+	// p, as taken by the calling function on entry, and returns its panic value;
+	// or nil if p is nil or has already been recovered.
+	//
+	//    func runtime.gorecover(p *_panic) interface{}
+	{
+		retType := m.newInterfaceType()
+		panicPtrType := irtypes.NewPointer(m.irTypeFromName("runtime._panic"))
+		param := ir.NewParam("p", panicPtrType)
+		gorecoverFunc := m.Module.NewFunc("runtime.gorecover", retType, param)
+		m.predeclaredFuncs[gorecoverFunc.Name()] = gorecoverFunc
+	}
+
+	// --- [ bounds checks ] ---
+
+	// runtime.panicbounds
+	//
+	// panicbounds panics on a failed bounds check of the index x against y.
+	//
+	//    func runtime.panicbounds(x int, signed bool, y int, kind uint8)
+	{
+		retType := irtypes.Void
+		params := []*ir.Param{
+			ir.NewParam("x", m.irTypeFromName("int")),
+			ir.NewParam("signed", m.irTypeFromName("bool")),
+			ir.NewParam("y", m.irTypeFromName("int")),
+			ir.NewParam("kind", m.irTypeFromName("uint8")),
+		}
+		panicboundsFunc := m.Module.NewFunc("runtime.panicbounds", retType, params...)
+		m.predeclaredFuncs[panicboundsFunc.Name()] = panicboundsFunc
+	}
+
+	// --- [ arithmetic checks ] ---
+
+	// runtime.panicdivide
+	//
+	// panicdivide panics on integer division by zero.
+	//
+	//    func runtime.panicdivide()
+	{
+		retType := irtypes.Void
+		panicdivideFunc := m.Module.NewFunc("runtime.panicdivide", retType)
+		m.predeclaredFuncs[panicdivideFunc.Name()] = panicdivideFunc
+	}
+
+	// runtime.panicshift
+	//
+	// panicshift panics on shifts by a negative shift count.
+	//
+	//    func runtime.panicshift()
+	{
+		retType := irtypes.Void
+		panicshiftFunc := m.Module.NewFunc("runtime.panicshift", retType)
+		m.predeclaredFuncs[panicshiftFunc.Name()] = panicshiftFunc
+	}
+
+	// --- [ defer ] ---
+
+	// runtime.deferproc
+	//
+	// deferproc pushes a heap-allocated defer record of the deferred call
+	// fn(arg) to the defer stack, deferred by the function with jump buffer
+	// frame.
+	//
+	//    func runtime.deferproc(fn func(arg unsafe.Pointer), arg, frame unsafe.Pointer)
+	{
+		retType := irtypes.Void
+		deferFuncType := irtypes.NewFunc(irtypes.Void, irtypes.I8Ptr)
+		params := []*ir.Param{
+			ir.NewParam("fn", irtypes.NewPointer(deferFuncType)),
+			ir.NewParam("arg", irtypes.I8Ptr),
+			ir.NewParam("frame", irtypes.I8Ptr),
+		}
+		deferprocFunc := m.Module.NewFunc("runtime.deferproc", retType, params...)
+		m.predeclaredFuncs[deferprocFunc.Name()] = deferprocFunc
+	}
+
+	// runtime.deferreturn
+	//
+	// deferreturn runs the pending deferred calls pushed to the defer stack
+	// above base, most recently deferred first.
+	//
+	//    func runtime.deferreturn(base *_defer)
+	{
+		retType := irtypes.Void
+		deferPtrType := irtypes.NewPointer(m.irTypeFromName("runtime._defer"))
+		param := ir.NewParam("base", deferPtrType)
+		deferreturnFunc := m.Module.NewFunc("runtime.deferreturn", retType, param)
+		m.predeclaredFuncs[deferreturnFunc.Name()] = deferreturnFunc
+	}
+
+	// _setjmp
+	{
+		// int _setjmp(jmp_buf env)
+		retType := irtypes.I32
+		param := ir.NewParam("env", irtypes.I8Ptr)
+		setjmpFunc := m.Module.NewFunc("_setjmp", retType, param)
+		// Returns a second time on long jumps to env.
+		setjmpFunc.FuncAttrs = append(setjmpFunc.FuncAttrs, irenum.FuncAttrReturnsTwice)
+		m.predeclaredFuncs[setjmpFunc.Name()] = setjmpFunc
+	}
+
+	// --- [ complex numbers ] ---
+
+	// runtime.complex128div
+	//
+	// complex128div returns the complex quotient n/m.
+	//
+	//    func runtime.complex128div(n, m complex128) complex128
+	{
+		complex128Type := m.irTypeFromName("complex128")
+		retType := complex128Type
+		params := []*ir.Param{
+			ir.NewParam("n", complex128Type),
+			ir.NewParam("m", complex128Type),
+		}
+		complex128divFunc := m.Module.NewFunc("runtime.complex128div", retType, params...)
+		m.predeclaredFuncs[complex128divFunc.Name()] = complex128divFunc
+	}
+
+	// --- [ hashing ] ---
+
+	// runtime.memhash
+	//
+	// memhash returns the hash of the n bytes at p, based on the given seed.
+	//
+	//    func runtime.memhash(p unsafe.Pointer, n int, seed uint64) uint64
+	{
+		retType := m.irTypeFromName("uint64")
+		params := []*ir.Param{
+			ir.NewParam("p", irtypes.I8Ptr),
+			ir.NewParam("n", m.irTypeFromName("int")),
+			ir.NewParam("seed", m.irTypeFromName("uint64")),
+		}
+		memhashFunc := m.Module.NewFunc("runtime.memhash", retType, params...)
+		m.predeclaredFuncs[memhashFunc.Name()] = memhashFunc
+	}
+
+	// runtime.strhash, runtime.f32hash, runtime.f64hash and runtime.interhash
+	//
+	// Hash functions of the value pointed to by p, based on the given seed.
+	//
+	//    func runtime.strhash(p *string, seed uint64) uint64
+	//    func runtime.f32hash(p *float32, seed uint64) uint64
+	//    func runtime.f64hash(p *float64, seed uint64) uint64
+	//    func runtime.interhash(p *interface{}, seed uint64) uint64
+	for _, hashFuncName := range []string{"runtime.strhash", "runtime.f32hash", "runtime.f64hash", "runtime.interhash"} {
+		retType := m.irTypeFromName("uint64")
+		params := []*ir.Param{
+			ir.NewParam("p", irtypes.I8Ptr),
+			ir.NewParam("seed", m.irTypeFromName("uint64")),
+		}
+		hashFunc := m.Module.NewFunc(hashFuncName, retType, params...)
+		m.predeclaredFuncs[hashFunc.Name()] = hashFunc
+	}
+
+	// --- [ maps ] ---
+
+	// runtime.makemap
+	//
+	// makemap returns a new map with the given key and element types, with room
+	// for approximately hint entries.
+	//
+	//    func runtime.makemap(key, elem *_type, hint int) *hmap
+	{
+		typePtrType := irtypes.NewPointer(m.irTypeFromName("runtime._type"))
+		retType := irtypes.NewPointer(m.irTypeFromName("runtime.hmap"))
+		params := []*ir.Param{
+			ir.NewParam("key", typePtrType),
+			ir.NewParam("elem", typePtrType),
+			ir.NewParam("hint", m.irTypeFromName("int")),
+		}
+		makemapFunc := m.Module.NewFunc("runtime.makemap", retType, params...)
+		m.predeclaredFuncs[makemapFunc.Name()] = makemapFunc
+	}
+
+	// runtime.mapaccess
+	//
+	// mapaccess returns a pointer to the element of h with the given key, or nil
+	// if not present.
+	//
+	//    func runtime.mapaccess(h *hmap, key unsafe.Pointer) unsafe.Pointer
+	{
+		hmapPtrType := irtypes.NewPointer(m.irTypeFromName("runtime.hmap"))
+		retType := irtypes.I8Ptr
+		params := []*ir.Param{
+			ir.NewParam("h", hmapPtrType),
+			ir.NewParam("key", irtypes.I8Ptr),
+		}
+		mapaccessFunc := m.Module.NewFunc("runtime.mapaccess", retType, params...)
+		m.predeclaredFuncs[mapaccessFunc.Name()] = mapaccessFunc
+	}
+
+	// runtime.mapassign
+	//
+	// mapassign returns a pointer to the element of h with the given key, for
+	// assignment. A new map entry is inserted if the key is not present.
+	//
+	//    func runtime.mapassign(h *hmap, key unsafe.Pointer) unsafe.Pointer
+	{
+		hmapPtrType := irtypes.NewPointer(m.irTypeFromName("runtime.hmap"))
+		retType := irtypes.I8Ptr
+		params := []*ir.Param{
+			ir.NewParam("h", hmapPtrType),
+			ir.NewParam("key", irtypes.I8Ptr),
+		}
+		mapassignFunc := m.Module.NewFunc("runtime.mapassign", retType, params...)
+		m.predeclaredFuncs[mapassignFunc.Name()] = mapassignFunc
+	}
+
+	// runtime.mapdelete
+	//
+	// mapdelete removes the entry with the given key from h, if present.
+	//
+	//    func runtime.mapdelete(h *hmap, key unsafe.Pointer)
+	{
+		hmapPtrType := irtypes.NewPointer(m.irTypeFromName("runtime.hmap"))
+		retType := irtypes.Void
+		params := []*ir.Param{
+			ir.NewParam("h", hmapPtrType),
+			ir.NewParam("key", irtypes.I8Ptr),
+		}
+		mapdeleteFunc := m.Module.NewFunc("runtime.mapdelete", retType, params...)
+		m.predeclaredFuncs[mapdeleteFunc.Name()] = mapdeleteFunc
+	}
+
+	// runtime.maplen
+	//
+	// maplen returns the number of entries of h.
+	//
+	//    func runtime.maplen(h *hmap) int
+	{
+		hmapPtrType := irtypes.NewPointer(m.irTypeFromName("runtime.hmap"))
+		retType := m.irTypeFromName("int")
+		param := ir.NewParam("h", hmapPtrType)
+		maplenFunc := m.Module.NewFunc("runtime.maplen", retType, param)
+		m.predeclaredFuncs[maplenFunc.Name()] = maplenFunc
+	}
+
+	// runtime.mapiterinit
+	//
+	// mapiterinit returns a new iterator over the entries of h.
+	//
+	//    func runtime.mapiterinit(h *hmap) *hiter
+	{
+		hmapPtrType := irtypes.NewPointer(m.irTypeFromName("runtime.hmap"))
+		retType := irtypes.NewPointer(m.irTypeFromName("runtime.hiter"))
+		param := ir.NewParam("h", hmapPtrType)
+		mapiterinitFunc := m.Module.NewFunc("runtime.mapiterinit", retType, param)
+		m.predeclaredFuncs[mapiterinitFunc.Name()] = mapiterinitFunc
+	}
+
+	// runtime.mapiternext
+	//
+	// mapiternext advances the iterator it, copying the key and element of the
+	// next map entry to the given addresses (if non-nil). The boolean result
+	// reports whether an entry was produced.
+	//
+	//    func runtime.mapiternext(it *hiter, key, elem unsafe.Pointer) bool
+	{
+		hiterPtrType := irtypes.NewPointer(m.irTypeFromName("runtime.hiter"))
+		retType := m.irTypeFromName("bool")
+		params := []*ir.Param{
+			ir.NewParam("it", hiterPtrType),
+			ir.NewParam("key", irtypes.I8Ptr),
+			ir.NewParam("elem", irtypes.I8Ptr),
+		}
+		mapiternextFunc := m.Module.NewFunc("runtime.mapiternext", retType, params...)
+		m.predeclaredFuncs[mapiternextFunc.Name()] = mapiternextFunc
+	}
 }
 
 // --- [ get ] -----------------------------------------------------------------
@@ -175,7 +743,13 @@ func (m *Module) indexFunc(goFunc *ssa.Function) error {
 	// TODO: use m.irTypeFromGo(goFunc.Signature) to simplify m.indexFunc.
 	// Convert Go function parameters to equivalent LLVM IR function parameters
 	// (including receiver of methods).
-	params := make([]*ir.Param, 0, len(goFunc.Params))
+	params := make([]*ir.Param, 0, len(goFunc.Params)+1)
+	if len(goFunc.FreeVars) > 0 {
+		// Closures receive a pointer to their captured variables as first
+		// argument.
+		ctx := ir.NewParam("ctx", irtypes.I8Ptr)
+		params = append(params, ctx)
+	}
 	for _, goParam := range goFunc.Params {
 		paramName := goParam.Name()
 		paramType := m.irTypeFromGo(goParam.Type())
@@ -223,6 +797,26 @@ func (m *Module) indexFunc(goFunc *ssa.Function) error {
 	return nil
 }
 
+// indexSynthFunc indexes the given synthetic Go SSA function (e.g. method
+// wrapper), creating a corresponding LLVM IR function, emitting to m. The
+// synthetic function is queued for compilation.
+//
+// Synthetic functions are created by Go SSA on demand, and are thus not members
+// of any Go SSA package. These include the wrappers of method sets (e.g.
+// "(*T).M" for a method M declared on T), bound method wrappers used for method
+// values (e.g. "(T).M$bound" for t.M) and thunks used for method expressions
+// (e.g. "(T).M$thunk" for T.M).
+func (m *Module) indexSynthFunc(goFunc *ssa.Function) error {
+	if err := m.indexFunc(goFunc); err != nil {
+		return errors.WithStack(err)
+	}
+	// Synthetic functions may be emitted by several LLVM IR modules.
+	f := m.getFunc(goFunc)
+	f.Linkage = irenum.LinkageLinkOnceODR
+	m.synthFuncs = append(m.synthFuncs, goFunc)
+	return nil
+}
+
 // --- [ compile ] -------------------------------------------------------------
 
 // emitFunc compiles the given Go SSA function into LLVM IR, emitting to m.
@@ -237,9 +831,17 @@ func (m *Module) emitFunc(goFunc *ssa.Function) error {
 	// Index Go SSA function parameters (including receiver of methods).
 	fn := m.NewFunc(goFunc)
 	dbg.Println("   funcName:", fn.Func.Name())
+	params := fn.Func.Params
+	if len(goFunc.FreeVars) > 0 {
+		// Index Go SSA free variables of closures, as loaded from the context
+		// pointer.
+		ctx := params[0]
+		params = params[1:]
+		fn.emitFreeVars(ctx)
+	}
 	for i, goParam := range goFunc.Params {
-		param := fn.Func.Params[i]
-		fn.locals[goParam] = param
+		param := params[i]
+		fn.setLocal(goParam, param)
 	}
 	// Index Go SSA basic blocks by creating corresponding LLVM IR basic blocks.
 	for _, goBlock := range goFunc.Blocks {
@@ -247,6 +849,10 @@ func (m *Module) emitFunc(goFunc *ssa.Function) error {
 			return errors.WithStack(err)
 		}
 	}
+	// Take the panic recoverable by the function and prepare deferred calls of
+	// function, emitting to the entry basic block.
+	fn.initRecoverPanic()
+	fn.initDefers()
 	// Add unconditional branch from LLVM IR entry basic block to Go SSA entry
 	// basic block.
 	entryBlock := fn.getBlock(goFunc.Blocks[0])
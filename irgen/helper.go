@@ -3,11 +3,18 @@ package irgen
 import (
 	"fmt"
 	gotypes "go/types"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/llir/llvm/ir"
+	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
 	"github.com/llir/llvm/ir/metadata"
 	irtypes "github.com/llir/llvm/ir/types"
 	irvalue "github.com/llir/llvm/ir/value"
+	"golang.org/x/tools/go/ssa"
 )
 
 // ### [ Helper functions ] ####################################################
@@ -32,6 +39,12 @@ type RelStringer interface {
 // fullName returns the full name of the value, qualified by package name if not
 // in main package.
 func (m *Module) fullName(v RelStringer) string {
+	if goFunc, ok := v.(*ssa.Function); ok && goFunc.Signature.Recv() != nil {
+		// Method (declared or wrapper); the receiver type may be a
+		// function-local type (for method wrappers of promoted methods).
+		recvType := goFunc.Signature.Recv().Type()
+		return fmt.Sprintf("(%s).%s", m.fullTypeName(recvType), goFunc.Name())
+	}
 	if m.skipPkgPrefix() {
 		// Fully qualified name if global is imported, otherwise name without
 		// package path.
@@ -49,10 +62,221 @@ func (m *Module) fullTypeName(t gotypes.Type) string {
 		// Fully qualified name if type is imported, otherwise name without
 		// package path.
 		from := m.goPkg.Pkg
-		return gotypes.TypeString(t, gotypes.RelativeTo(from))
+		return m.typeName(t, gotypes.RelativeTo(from))
 	}
 	// Fully qualified name (with package path).
-	return gotypes.TypeString(t, nil)
+	return m.typeName(t, nil)
+}
+
+// typeName returns the name of the given Go type, which uniquely identifies the
+// type. Named types, and unexported struct fields and interface methods, are
+// qualified by the package name returned by qf, or by package path if qf is nil.
+//
+// The type name is the string representation of the type (as used by go/types)
+// with three exceptions; function-local named types are given unique names
+// (e.g. "pair#1"), unexported field and method names are package qualified (as
+// done by types.Id) since they are distinct across packages, and parameter
+// names are omitted from function signatures as they do not affect type
+// identity.
+func (m *Module) typeName(t gotypes.Type, qf gotypes.Qualifier) string {
+	buf := &strings.Builder{}
+	m.writeTypeName(buf, t, qf)
+	return buf.String()
+}
+
+// writeTypeName writes the name of the given Go type to buf. Named types, and
+// unexported struct fields and interface methods, are qualified by the package
+// name returned by qf, or by package path if qf is nil.
+func (m *Module) writeTypeName(buf *strings.Builder, t gotypes.Type, qf gotypes.Qualifier) {
+	switch t := t.(type) {
+	case *gotypes.Named:
+		obj := t.Obj()
+		writePkgQualifier(buf, obj.Pkg(), qf)
+		if isLocalTypeName(obj) {
+			buf.WriteString(m.localTypeName(obj))
+		} else {
+			buf.WriteString(obj.Name())
+		}
+	case *gotypes.Pointer:
+		buf.WriteString("*")
+		m.writeTypeName(buf, t.Elem(), qf)
+	case *gotypes.Slice:
+		buf.WriteString("[]")
+		m.writeTypeName(buf, t.Elem(), qf)
+	case *gotypes.Array:
+		fmt.Fprintf(buf, "[%d]", t.Len())
+		m.writeTypeName(buf, t.Elem(), qf)
+	case *gotypes.Map:
+		buf.WriteString("map[")
+		m.writeTypeName(buf, t.Key(), qf)
+		buf.WriteString("]")
+		m.writeTypeName(buf, t.Elem(), qf)
+	case *gotypes.Chan:
+		parens := false
+		switch t.Dir() {
+		case gotypes.SendRecv:
+			buf.WriteString("chan ")
+			// chan (<-chan T) requires parentheses.
+			if elem, ok := t.Elem().(*gotypes.Chan); ok && elem.Dir() == gotypes.RecvOnly {
+				parens = true
+			}
+		case gotypes.SendOnly:
+			buf.WriteString("chan<- ")
+		case gotypes.RecvOnly:
+			buf.WriteString("<-chan ")
+		}
+		if parens {
+			buf.WriteString("(")
+		}
+		m.writeTypeName(buf, t.Elem(), qf)
+		if parens {
+			buf.WriteString(")")
+		}
+	case *gotypes.Struct:
+		buf.WriteString("struct{")
+		for i := 0; i < t.NumFields(); i++ {
+			if i > 0 {
+				buf.WriteString("; ")
+			}
+			field := t.Field(i)
+			if !field.Embedded() {
+				if !field.Exported() {
+					writePkgQualifier(buf, field.Pkg(), qf)
+				}
+				buf.WriteString(field.Name())
+				buf.WriteString(" ")
+			} else if !field.Exported() && !isNamedTypeOf(field.Type(), field.Pkg()) {
+				// The name of embedded fields is given by their type name, which
+				// is already package qualified for named types of the package
+				// declaring the field.
+				writePkgQualifier(buf, field.Pkg(), qf)
+			}
+			m.writeTypeName(buf, field.Type(), qf)
+			if tag := t.Tag(i); len(tag) > 0 {
+				buf.WriteString(" ")
+				buf.WriteString(strconv.Quote(tag))
+			}
+		}
+		buf.WriteString("}")
+	case *gotypes.Tuple:
+		m.writeTupleName(buf, t, false, qf)
+	case *gotypes.Signature:
+		buf.WriteString("func")
+		m.writeSignatureName(buf, t, qf)
+	case *gotypes.Interface:
+		buf.WriteString("interface{")
+		// Write the complete method set, including the methods of embedded
+		// interfaces, sorted by method name.
+		for i := 0; i < t.NumMethods(); i++ {
+			if i > 0 {
+				buf.WriteString("; ")
+			}
+			method := t.Method(i)
+			if !method.Exported() {
+				writePkgQualifier(buf, method.Pkg(), qf)
+			}
+			buf.WriteString(method.Name())
+			m.writeSignatureName(buf, method.Type().(*gotypes.Signature), qf)
+		}
+		buf.WriteString("}")
+	default:
+		// Basic types.
+		buf.WriteString(gotypes.TypeString(t, qf))
+	}
+}
+
+// writePkgQualifier writes the package name of pkg returned by qf, or the
+// package path if qf is nil, followed by a dot to buf. Nothing is written if pkg
+// is nil (e.g. for predeclared types and the fields of structures synthesized by
+// irgen), or if the package name is empty.
+func writePkgQualifier(buf *strings.Builder, pkg *gotypes.Package, qf gotypes.Qualifier) {
+	if pkg == nil {
+		return
+	}
+	pkgName := pkg.Path()
+	if qf != nil {
+		pkgName = qf(pkg)
+	}
+	if len(pkgName) > 0 {
+		buf.WriteString(pkgName)
+		buf.WriteString(".")
+	}
+}
+
+// writeTupleName writes the name of the given Go tuple type to buf. The last
+// element of variadic tuples is written as "...T".
+func (m *Module) writeTupleName(buf *strings.Builder, t *gotypes.Tuple, variadic bool, qf gotypes.Qualifier) {
+	buf.WriteString("(")
+	for i := 0; i < t.Len(); i++ {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		typ := t.At(i).Type()
+		if variadic && i == t.Len()-1 {
+			buf.WriteString("...")
+			typ = typ.(*gotypes.Slice).Elem()
+		}
+		m.writeTypeName(buf, typ, qf)
+	}
+	buf.WriteString(")")
+}
+
+// writeSignatureName writes the name of the given Go function signature to buf,
+// without the "func" keyword and receiver.
+func (m *Module) writeSignatureName(buf *strings.Builder, t *gotypes.Signature, qf gotypes.Qualifier) {
+	m.writeTupleName(buf, t.Params(), t.Variadic(), qf)
+	switch results := t.Results(); results.Len() {
+	case 0:
+		// no results.
+	case 1:
+		buf.WriteString(" ")
+		m.writeTypeName(buf, results.At(0).Type(), qf)
+	default:
+		buf.WriteString(" ")
+		m.writeTupleName(buf, results, false, qf)
+	}
+}
+
+// localTypeName returns the unique name of the given function-local Go type
+// name (e.g. "pair#1"). Local types are numbered in source order, starting at 1
+// in each package.
+func (m *Module) localTypeName(obj *gotypes.TypeName) string {
+	if name, ok := m.localTypeNames[obj]; ok {
+		return name
+	}
+	// Index the local type names of the package.
+	var objs []*gotypes.TypeName
+	var walk func(scope *gotypes.Scope)
+	walk = func(scope *gotypes.Scope) {
+		for _, name := range scope.Names() {
+			if obj, ok := scope.Lookup(name).(*gotypes.TypeName); ok {
+				objs = append(objs, obj)
+			}
+		}
+		for i := 0; i < scope.NumChildren(); i++ {
+			walk(scope.Child(i))
+		}
+	}
+	pkgScope := obj.Pkg().Scope()
+	for i := 0; i < pkgScope.NumChildren(); i++ {
+		walk(pkgScope.Child(i))
+	}
+	sort.Slice(objs, func(i, j int) bool {
+		return objs[i].Pos() < objs[j].Pos()
+	})
+	for i, obj := range objs {
+		m.localTypeNames[obj] = fmt.Sprintf("%s#%d", obj.Name(), i+1)
+	}
+	if name, ok := m.localTypeNames[obj]; ok {
+		return name
+	}
+	panic(fmt.Errorf("unable to locate function-local type %q in scope of package %q", obj.Name(), obj.Pkg().Path()))
+}
+
+// isLocalTypeName reports whether the given Go type name is declared within a
+// function.
+func isLocalTypeName(obj *gotypes.TypeName) bool {
+	return obj.Pkg() != nil && obj.Parent() != nil && obj.Parent() != obj.Pkg().Scope()
 }
 
 // precFromFloatKind return the precision of the given LLVM IR floating-point
@@ -244,20 +468,32 @@ func addMetadata(v ir.LLStringer, key, val string) {
 	}
 }
 
-// isSigned reports whether the given integer type is signed.
-func (m *Module) isSigned(typ *irtypes.IntType) bool {
-	switch typ.Name() {
-	case "uint8", "uint16", "uint32", "uint64", "uint", "uintptr":
-		return false
-	case "int8", "int16", "int32", "int64", "int":
-		return true
-	case "byte": // alias for uint8
-		return false
-	case "rune": // alias for int32
-		return true
-	default:
-		panic(fmt.Errorf("support for integer type name %q not yet implemented", typ.Name()))
+// isSigned reports whether the given Go type is a signed integer type.
+func isSigned(goType gotypes.Type) bool {
+	t, ok := goType.Underlying().(*gotypes.Basic)
+	return ok && t.Info()&gotypes.IsInteger != 0 && t.Info()&gotypes.IsUnsigned == 0
+}
+
+// isComplexType reports whether the given Go type is a complex type.
+func isComplexType(goType gotypes.Type) bool {
+	t, ok := goType.Underlying().(*gotypes.Basic)
+	return ok && t.Info()&gotypes.IsComplex != 0
+}
+
+// isNamedTypeOf reports whether the given Go type is a named type declared in
+// the specified package, or a pointer to such a type.
+func isNamedTypeOf(goType gotypes.Type, pkg *gotypes.Package) bool {
+	if t, ok := goType.(*gotypes.Pointer); ok {
+		goType = t.Elem()
 	}
+	t, ok := goType.(*gotypes.Named)
+	return ok && t.Obj().Pkg() == pkg
+}
+
+// isPointerType reports whether the given Go type is a pointer type.
+func isPointerType(goType gotypes.Type) bool {
+	_, ok := goType.Underlying().(*gotypes.Pointer)
+	return ok
 }
 
 // skipPkgPrefix reports whether to skip the package prefix in qualified names.
@@ -277,9 +513,10 @@ func (m *Module) skipPkgPrefix() bool {
 
 // --- [ convert ] -------------------------------------------------------------
 
-// convert converts the given the given value to the specified type, emitting to
-// fn.
-func (fn *Func) convert(from irvalue.Value, to irtypes.Type) irValueInstruction {
+// convert converts the given value from Go type goFrom to Go type goTo,
+// emitting to fn.
+func (fn *Func) convert(from irvalue.Value, goFrom, goTo gotypes.Type) irValueInstruction {
+	to := fn.m.irTypeFromGo(goTo)
 	var inst irValueInstruction
 	switch fromType := from.Type().(type) {
 	case *irtypes.IntType:
@@ -290,7 +527,7 @@ func (fn *Func) convert(from irvalue.Value, to irtypes.Type) irValueInstruction
 			case fromType.BitSize == toType.BitSize:
 				inst = fn.cur.NewBitCast(from, to)
 			case fromType.BitSize < toType.BitSize:
-				if fn.m.isSigned(fromType) {
+				if isSigned(goFrom) {
 					inst = fn.cur.NewSExt(from, to)
 				} else {
 					inst = fn.cur.NewZExt(from, to)
@@ -302,7 +539,7 @@ func (fn *Func) convert(from irvalue.Value, to irtypes.Type) irValueInstruction
 			}
 		// int -> float
 		case *irtypes.FloatType:
-			if fn.m.isSigned(fromType) {
+			if isSigned(goFrom) {
 				inst = fn.cur.NewSIToFP(from, to)
 			} else {
 				inst = fn.cur.NewUIToFP(from, to)
@@ -315,11 +552,7 @@ func (fn *Func) convert(from irvalue.Value, to irtypes.Type) irValueInstruction
 		switch toType := to.(type) {
 		// float -> int
 		case *irtypes.IntType:
-			if fn.m.isSigned(toType) {
-				inst = fn.cur.NewFPToSI(from, to)
-			} else {
-				inst = fn.cur.NewFPToUI(from, to)
-			}
+			inst = fn.emitFloatToInt(from, toType, isSigned(goTo))
 		// float -> float
 		case *irtypes.FloatType:
 			fromPrec := precFromFloatKind(fromType.Kind)
@@ -341,6 +574,17 @@ func (fn *Func) convert(from irvalue.Value, to irtypes.Type) irValueInstruction
 		default:
 			panic(fmt.Errorf("support for converting from type %T (%v) to type %T (%v) not yet implemented", fromType, fromType, to, to))
 		}
+	case *irtypes.StructType:
+		switch toType := to.(type) {
+		// complex -> complex
+		case *irtypes.StructType:
+			if !isComplexType(goFrom) || !isComplexType(goTo) {
+				panic(fmt.Errorf("support for converting from type %T (%v) to type %T (%v) not yet implemented", fromType, fromType, to, to))
+			}
+			inst = fn.emitComplexConvert(from, toType)
+		default:
+			panic(fmt.Errorf("support for converting from type %T (%v) to type %T (%v) not yet implemented", fromType, fromType, to, to))
+		}
 	case *irtypes.PointerType:
 		switch to.(type) {
 		// pointer -> pointer
@@ -356,3 +600,100 @@ func (fn *Func) convert(from irvalue.Value, to irtypes.Type) irValueInstruction
 	}
 	return inst
 }
+
+// ~~~ [ floating-point to integer conversion ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// emitFloatToInt converts the given floating-point value to the specified
+// integer type, emitting to fn. The signed flag reports whether the integer
+// type is signed.
+//
+// The result of converting values out of range of the integer type (including
+// NaN and infinities) is implementation-defined in Go, but poison in LLVM IR.
+// Such conversions produce the same result as gc on amd64: conversions to int64
+// and int32 produce the most negative value of the type; int16, int8, uint16
+// and uint8 are converted through int32, and uint32 through int64; uint64
+// values of at least 1<<63 are converted as x-(1<<63) through int64, with the
+// most significant bit set.
+func (fn *Func) emitFloatToInt(x irvalue.Value, to *irtypes.IntType, signed bool) irValueInstruction {
+	switch {
+	case to.BitSize == 64 && !signed:
+		fromType := x.Type().(*irtypes.FloatType)
+		limit := irconstant.NewFloat(fromType, 1<<63)
+		small := fn.cur.NewFCmp(irenum.FPredOLT, x, limit)
+		lo := fn.emitFloatToIntTrunc(x, to)
+		hi := fn.emitFloatToIntTrunc(fn.cur.NewFSub(x, limit), to)
+		hi = fn.cur.NewOr(hi, irconstant.NewInt(to, math.MinInt64))
+		return fn.cur.NewSelect(small, lo, hi)
+	case to.BitSize == 64, to.BitSize == 32 && signed:
+		return fn.emitFloatToIntTrunc(x, to)
+	case to.BitSize == 32:
+		return fn.cur.NewTrunc(fn.emitFloatToIntTrunc(x, irtypes.I64), to)
+	default:
+		return fn.cur.NewTrunc(fn.emitFloatToIntTrunc(x, irtypes.I32), to)
+	}
+}
+
+// emitFloatToIntTrunc converts the given floating-point value to the specified
+// signed integer type of 32 or 64 bits, rounding towards zero, emitting to fn.
+// Values out of range produce the most negative value of the integer type (as
+// by CVTTSD2SQ on amd64).
+func (fn *Func) emitFloatToIntTrunc(x irvalue.Value, to *irtypes.IntType) irValueInstruction {
+	fromType := x.Type().(*irtypes.FloatType)
+	// -(1<<(n-1)) <= x < 1<<(n-1); false for NaN.
+	limit := math.Ldexp(1, int(to.BitSize-1))
+	geMin := fn.cur.NewFCmp(irenum.FPredOGE, x, irconstant.NewFloat(fromType, -limit))
+	ltMax := fn.cur.NewFCmp(irenum.FPredOLT, x, irconstant.NewFloat(fromType, limit))
+	inRange := fn.cur.NewAnd(geMin, ltMax)
+	v := fn.cur.NewFPToSI(x, to)
+	min := irconstant.NewInt(to, -1<<(to.BitSize-1))
+	return fn.cur.NewSelect(inRange, v, min)
+}
+
+// --- [ cast ] ----------------------------------------------------------------
+
+// emitElemPtr returns the given pointer x of the specified Go pointer type as
+// an LLVM IR pointer to the element type, emitting to fn.
+//
+// Named pointer types referring to themselves (e.g. `type P *P`) are
+// represented by placeholder pointer types (see irTypeFromGo). The value is
+// returned as is if already a pointer to the element type.
+func (fn *Func) emitElemPtr(x irvalue.Value, goPtrType gotypes.Type) irvalue.Value {
+	goElemType := goPtrType.Underlying().(*gotypes.Pointer).Elem()
+	typ := irtypes.NewPointer(fn.m.irTypeFromGo(goElemType))
+	if x.Type().LLString() == typ.LLString() {
+		return x
+	}
+	return fn.cur.NewBitCast(x, typ)
+}
+
+// cast converts the given value to the specified LLVM IR type of identical
+// memory layout, emitting to fn.
+//
+// Values of defined Go types (e.g. `type S string`) are represented by named
+// LLVM IR types, which are distinct from the LLVM IR type of the underlying Go
+// type (e.g. %S and %string). The value is returned as is if already of the
+// specified type.
+func (fn *Func) cast(x irvalue.Value, typ irtypes.Type) irvalue.Value {
+	if irtypes.Equal(x.Type(), typ) {
+		return x
+	}
+	switch t := typ.(type) {
+	case *irtypes.PointerType:
+		return fn.cur.NewBitCast(x, t)
+	case *irtypes.StructType:
+		var v irvalue.Value = irconstant.NewZeroInitializer(t)
+		for i, field := range t.Fields {
+			f := fn.cur.NewExtractValue(x, uint64(i))
+			v = fn.cur.NewInsertValue(v, fn.cast(f, field), uint64(i))
+		}
+		return v
+	case *irtypes.ArrayType:
+		// Reinterpret array through memory, to not extract each element.
+		slot := fn.entry.NewAlloca(x.Type())
+		fn.cur.NewStore(x, slot)
+		ptr := fn.cur.NewBitCast(slot, irtypes.NewPointer(t))
+		return fn.cur.NewLoad(t, ptr)
+	default:
+		panic(fmt.Errorf("support for casting from type %v to type %v not yet implemented", x.Type(), typ))
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"go/token"
 	gotypes "go/types"
-	"strings"
 
 	"github.com/llir/llvm/ir"
 	irconstant "github.com/llir/llvm/ir/constant"
@@ -39,8 +38,7 @@ func (fn *Func) emitInst(goInst ssa.Instruction) error {
 		goInst.Parent().WriteTo(ssaDebugWriter)
 		panic("support for *ssa.DebugRef not yet implemented")
 	case *ssa.Defer:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic("support for *ssa.Defer not yet implemented")
+		return fn.emitDefer(goInst)
 	case *ssa.Go:
 		goInst.Parent().WriteTo(ssaDebugWriter)
 		panic("support for *ssa.Go not yet implemented")
@@ -49,16 +47,13 @@ func (fn *Func) emitInst(goInst ssa.Instruction) error {
 	case *ssa.Jump:
 		return fn.emitJump(goInst)
 	case *ssa.MapUpdate:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic("support for *ssa.MapUpdate not yet implemented")
+		return fn.emitMapUpdate(goInst)
 	case *ssa.Panic:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic("support for *ssa.Panic not yet implemented")
+		return fn.emitPanic(goInst)
 	case *ssa.Return:
 		return fn.emitReturn(goInst)
 	case *ssa.RunDefers:
-		// TODO: implement support for defer.
-		return nil // ignore *ssa.RunDefers instruction for now
+		return fn.emitRunDefers(goInst)
 	case *ssa.Send:
 		goInst.Parent().WriteTo(ssaDebugWriter)
 		panic("support for *ssa.Send not yet implemented")
@@ -82,20 +77,17 @@ func (fn *Func) emitValueInst(goInst ssaValueInstruction) error {
 	case *ssa.ChangeInterface:
 		return fn.emitChangeInterface(goInst)
 	case *ssa.ChangeType:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.ChangeType (in %q) not yet implemented", goInst.Name()))
+		return fn.emitChangeType(goInst)
 	case *ssa.Convert:
 		return fn.emitConvert(goInst)
 	case *ssa.Extract:
 		return fn.emitExtract(goInst)
 	case *ssa.Field:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.Field (in %q) not yet implemented", goInst.Name()))
+		return fn.emitField(goInst)
 	case *ssa.FieldAddr:
 		return fn.emitFieldAddr(goInst)
 	case *ssa.Index:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.Index (in %q) not yet implemented", goInst.Name()))
+		return fn.emitIndex(goInst)
 	case *ssa.IndexAddr:
 		return fn.emitIndexAddr(goInst)
 	case *ssa.Lookup:
@@ -104,33 +96,26 @@ func (fn *Func) emitValueInst(goInst ssaValueInstruction) error {
 		goInst.Parent().WriteTo(ssaDebugWriter)
 		panic(fmt.Errorf("support for *ssa.MakeChan (in %q) not yet implemented", goInst.Name()))
 	case *ssa.MakeClosure:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.MakeClosure (in %q) not yet implemented", goInst.Name()))
+		return fn.emitMakeClosure(goInst)
 	case *ssa.MakeInterface:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.MakeInterface (in %q) not yet implemented", goInst.Name()))
+		return fn.emitMakeInterface(goInst)
 	case *ssa.MakeMap:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.MakeMap (in %q) not yet implemented", goInst.Name()))
+		return fn.emitMakeMap(goInst)
 	case *ssa.MakeSlice:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.MakeSlice (in %q) not yet implemented", goInst.Name()))
+		return fn.emitMakeSlice(goInst)
 	case *ssa.Next:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.Next (in %q) not yet implemented", goInst.Name()))
+		return fn.emitNext(goInst)
 	case *ssa.Phi:
 		return fn.emitPhi(goInst)
 	case *ssa.Range:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.Range (in %q) not yet implemented", goInst.Name()))
+		return fn.emitRange(goInst)
 	case *ssa.Select:
 		goInst.Parent().WriteTo(ssaDebugWriter)
 		panic(fmt.Errorf("support for *ssa.Select (in %q) not yet implemented", goInst.Name()))
 	case *ssa.Slice:
 		return fn.emitSlice(goInst)
 	case *ssa.TypeAssert:
-		goInst.Parent().WriteTo(ssaDebugWriter)
-		panic(fmt.Errorf("support for *ssa.TypeAssert (in %q) not yet implemented", goInst.Name()))
+		return fn.emitTypeAssert(goInst)
 	case *ssa.UnOp:
 		return fn.emitUnOp(goInst)
 	default:
@@ -205,7 +190,7 @@ func (fn *Func) emitReturn(goInst *ssa.Return) error {
 			return errors.Errorf("invalid return type for function %q with multiple return values (%d); expected *irtypes.StructType, got %T", fn.Func.Name(), len(results), fn.Func.Sig.RetType)
 		}
 		if len(structType.Fields) != len(results) {
-			return errors.Errorf("mismatch between number of results in function signature (%d) and function return values (%d) in function %q", len(structType.Fields), len(results))
+			return errors.Errorf("mismatch between number of results in function signature (%d) and function return values (%d) in function %q", len(structType.Fields), len(results), fn.Func.Name())
 		}
 		alloca := fn.entry.NewAlloca(structType)
 		fn.cur.NewStore(irconstant.NewZeroInitializer(structType), alloca)
@@ -220,13 +205,45 @@ func (fn *Func) emitReturn(goInst *ssa.Return) error {
 	return nil
 }
 
+// --- [ mapupdate instruction ] -----------------------------------------------
+
+// emitMapUpdate compiles the given Go SSA mapupdate instruction to
+// corresponding LLVM IR instructions, emitting to fn.
+func (fn *Func) emitMapUpdate(goInst *ssa.MapUpdate) error {
+	dbg.Println("emitMapUpdate")
+	m := fn.useValue(goInst.Map)
+	key := fn.useValue(goInst.Key)
+	val := fn.useValue(goInst.Value)
+	fn.emitMapAssign(m, key, val)
+	return nil
+}
+
+// --- [ panic instruction ] ---------------------------------------------------
+
+// emitPanic compiles the given Go SSA panic instruction to corresponding LLVM
+// IR instructions, emitting to fn.
+func (fn *Func) emitPanic(goInst *ssa.Panic) error {
+	dbg.Println("emitPanic")
+	// The panic value is an empty interface value.
+	x := fn.useValue(goInst.X)
+	tab := fn.cur.NewExtractValue(x, 0)
+	addMetadata(tab, "field", "tab")
+	data := fn.cur.NewExtractValue(x, 1)
+	addMetadata(data, "field", "data")
+	gopanic := fn.m.getPredeclaredFunc("runtime.gopanic")
+	fn.cur.NewCall(gopanic, tab, data)
+	term := fn.cur.NewUnreachable()
+	dbg.Println("   term:", term.LLString())
+	return nil
+}
+
 // --- [ store instruction ] ---------------------------------------------------
 
 // emitStore compiles the given Go SSA store instruction to corresponding LLVM
 // IR instructions, emitting to fn.
 func (fn *Func) emitStore(goInst *ssa.Store) error {
 	dbg.Println("emitStore")
-	addr := fn.useValue(goInst.Addr)
+	addr := fn.emitElemPtr(fn.useValue(goInst.Addr), goInst.Addr.Type())
 	dbg.Println("   addr:", addr)
 	val := fn.useValue(goInst.Val)
 	dbg.Println("   val:", val)
@@ -251,7 +268,7 @@ func (fn *Func) emitAlloc(goInst *ssa.Alloc) error {
 	ptrType := typ.(*irtypes.PointerType)
 	inst := fn.cur.NewAlloca(ptrType.ElemType)
 	inst.SetName(goInst.Name())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
 	// Add local variable name metadata attachment to alloca instruction.
 	if len(goInst.Comment) > 0 {
 		addMetadata(inst, "var_name", goInst.Comment)
@@ -266,57 +283,16 @@ func (fn *Func) emitAlloc(goInst *ssa.Alloc) error {
 
 // ~~~ [ new - heap alloc instruction ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
-// TODO: move emitNew to builtin, where other synthesized builtin functions are
-// handled.
-
 // emitNew compiles the given Go SSA heap alloc instruction to corresponding
 // LLVM IR instructions, emitting to fn.
 func (fn *Func) emitNew(goInst *ssa.Alloc) error {
 	dbg.Println("emitNew")
-	typ := fn.m.irTypeFromGo(goInst.Type())
-	ptrType := typ.(*irtypes.PointerType)
-	// Define `new(T)` function if not present.
-	typeName := goInst.Type().(*gotypes.Pointer).Elem().String()
-	newFuncName := fmt.Sprintf("new(%s)", typeName)
-	newFunc, ok := fn.m.predeclaredFuncs[newFuncName]
-	if !ok {
-		retType := ptrType
-		newFunc = fn.m.Module.NewFunc(newFuncName, retType)
-		entry := newFunc.NewBlock("entry")
-		allocaInst := entry.NewAlloca(ptrType.ElemType)
-		objectsizeFunc := fn.m.getPredeclaredFunc("llvm.objectsize.i64")
-		bitCastInst := entry.NewBitCast(allocaInst, irtypes.I8Ptr)
-		objectsizeArgs := []irvalue.Value{
-			bitCastInst,      // object
-			irconstant.False, // min
-			irconstant.False, // nullunknown
-			irconstant.False, // dynamic
-		}
-		size := entry.NewCall(objectsizeFunc, objectsizeArgs...)
-		size.SetName("size")
-		cond := entry.NewICmp(irenum.IPredNE, size, irconstant.NewInt(irtypes.I64, -1))
-		success := newFunc.NewBlock("success")
-		fail := newFunc.NewBlock("fail")
-		entry.NewCondBr(cond, success, fail)
-		// Generate `success` basic block.
-		callocFunc := fn.m.getPredeclaredFunc("calloc") // using calloc to zero initialize
-		args := []irvalue.Value{
-			irconstant.NewInt(irtypes.I64, 1),
-			size,
-		}
-		callInst := success.NewCall(callocFunc, args...)
-		result := success.NewBitCast(callInst, ptrType)
-		success.NewRet(result)
-		// Generate `fail` basic block.
-		// TODO: panic with "unable to get size of type T" error message.
-		fail.NewUnreachable()
-		// Add synthesized `new(T)` function to predeclared functions.
-		fn.m.predeclaredFuncs[newFunc.Name()] = newFunc
-	}
 	// Invoke new(T).
+	goElemType := goInst.Type().(*gotypes.Pointer).Elem()
+	newFunc := fn.m.synthNew(goElemType)
 	inst := fn.cur.NewCall(newFunc)
 	inst.SetName(goInst.Name())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
 	// Add local variable name metadata attachment to alloca instruction.
 	if len(goInst.Comment) > 0 {
 		addMetadata(inst, "comment", goInst.Comment)
@@ -332,10 +308,32 @@ func (fn *Func) emitNew(goInst *ssa.Alloc) error {
 func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 	dbg.Println("emitBinOp")
 	dbg.Println("   op:", goInst.Op)
+	if isStringConcat(goInst) {
+		if isStringConcatOperand(goInst) {
+			// Intermediate results of n-ary string concatenations are not
+			// needed; the operands are concatenated at once by the outermost
+			// concatenation.
+			return nil
+		}
+		inst := fn.emitStringConcat(goInst)
+		inst.SetName(goInst.Name())
+		fn.setLocal(goInst, inst)
+		dbg.Println("   inst:", inst.LLString())
+		return nil
+	}
 	x := fn.useValue(goInst.X)
 	dbg.Println("   x:", x)
 	y := fn.useValue(goInst.Y)
 	dbg.Println("   y:", y)
+	// Operand type; lowering decisions are made from the Go type, as the LLVM IR
+	// type of defined types does not carry signedness.
+	goType := goInst.X.Type()
+	if isStringType(goType) {
+		// Compare values of defined string types as strings.
+		stringType := fn.m.irTypeFromName("string")
+		x = fn.cast(x, stringType)
+		y = fn.cast(y, stringType)
+	}
 	var inst irValueInstruction
 	switch goInst.Op {
 	// ADD (+)
@@ -346,15 +344,12 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 		case *irtypes.FloatType:
 			inst = fn.cur.NewFAdd(x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "complex64", "complex128":
+			switch {
+			case isComplexType(goType):
 				op := func(a, b irvalue.Value) irValueInstruction {
 					return fn.cur.NewFAdd(a, b)
 				}
 				inst = fn.emitComplexBinOp(op, x, y)
-			case "string":
-				// TODO: add support for string type.
-				panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 			default:
 				panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 			}
@@ -369,8 +364,8 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 		case *irtypes.FloatType:
 			inst = fn.cur.NewFSub(x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "complex64", "complex128":
+			switch {
+			case isComplexType(goType):
 				op := func(a, b irvalue.Value) irValueInstruction {
 					return fn.cur.NewFSub(a, b)
 				}
@@ -389,12 +384,9 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 		case *irtypes.FloatType:
 			inst = fn.cur.NewFMul(x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "complex64", "complex128":
-				op := func(a, b irvalue.Value) irValueInstruction {
-					return fn.cur.NewFMul(a, b)
-				}
-				inst = fn.emitComplexBinOp(op, x, y)
+			switch {
+			case isComplexType(goType):
+				inst = fn.emitComplexMul(x, y)
 			default:
 				panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 			}
@@ -405,20 +397,13 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 	case token.QUO: // /
 		switch typ := x.Type().(type) {
 		case *irtypes.IntType:
-			if fn.m.isSigned(typ) {
-				inst = fn.cur.NewSDiv(x, y)
-			} else {
-				inst = fn.cur.NewUDiv(x, y)
-			}
+			inst = fn.emitIntDiv(goInst.Op, x, y, isSigned(goType))
 		case *irtypes.FloatType:
 			inst = fn.cur.NewFDiv(x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "complex64", "complex128":
-				op := func(a, b irvalue.Value) irValueInstruction {
-					return fn.cur.NewFDiv(a, b)
-				}
-				inst = fn.emitComplexBinOp(op, x, y)
+			switch {
+			case isComplexType(goType):
+				inst = fn.emitComplexDiv(x, y)
 			default:
 				panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 			}
@@ -429,11 +414,7 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 	case token.REM: // %
 		switch typ := x.Type().(type) {
 		case *irtypes.IntType:
-			if fn.m.isSigned(typ) {
-				inst = fn.cur.NewSRem(x, y)
-			} else {
-				inst = fn.cur.NewURem(x, y)
-			}
+			inst = fn.emitIntDiv(goInst.Op, x, y, isSigned(goType))
 		default:
 			panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 		}
@@ -448,22 +429,14 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 		inst = fn.cur.NewXor(x, y)
 	// SHL (<<)
 	case token.SHL: // <<
-		if !irtypes.Equal(x.Type(), y.Type()) {
-			y = fn.convert(y, x.Type())
-		}
-		inst = fn.cur.NewShl(x, y)
+		count, signedCount := fn.emitShiftCount(goInst.Y)
+		inst = fn.emitShift(goInst.Op, x, count, isSigned(goType), signedCount)
 	// SHR (>>)
 	case token.SHR: // >>
-		if !irtypes.Equal(x.Type(), y.Type()) {
-			y = fn.convert(y, x.Type())
-		}
 		switch typ := x.Type().(type) {
 		case *irtypes.IntType:
-			if fn.m.isSigned(typ) {
-				inst = fn.cur.NewAShr(x, y)
-			} else {
-				inst = fn.cur.NewLShr(x, y)
-			}
+			count, signedCount := fn.emitShiftCount(goInst.Y)
+			inst = fn.emitShift(goInst.Op, x, count, isSigned(goType), signedCount)
 		default:
 			panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 		}
@@ -471,8 +444,8 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 	case token.AND_NOT: // &^
 		switch typ := x.Type().(type) {
 		case *irtypes.IntType:
-			zero := irconstant.NewInt(typ, 0)
-			tmp := fn.cur.NewXor(y, zero)
+			minusOne := irconstant.NewInt(typ, -1)
+			tmp := fn.cur.NewXor(y, minusOne)
 			dbg.Println("   tmp:", tmp.LLString())
 			inst = fn.cur.NewAnd(x, tmp)
 		default:
@@ -484,18 +457,27 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 		case *irtypes.IntType:
 			inst = fn.cur.NewICmp(irenum.IPredEQ, x, y)
 		case *irtypes.FloatType:
-			// TODO: figure out when to use FPredOEQ vs. FPredUEQ (ordered vs.
-			// unordered).
+			// Ordered comparison; NaN is not equal to any value, including NaN.
 			inst = fn.cur.NewFCmp(irenum.FPredOEQ, x, y)
+		case *irtypes.PointerType:
+			// Pointers and maps.
+			inst = fn.cur.NewICmp(irenum.IPredEQ, x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "complex64", "complex128":
-				panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
-			case "string":
-				cmp := fn.m.getPredeclaredFunc("cmp.string")
-				result := fn.cur.NewCall(cmp, x, y)
-				zero := irconstant.NewInt(result.Type().(*irtypes.IntType), 0)
-				inst = fn.cur.NewICmp(irenum.IPredEQ, result, zero)
+			switch {
+			case gotypes.IsInterface(goType):
+				inst = fn.emitInterfaceEqual(x, y)
+			case isFuncType(goType):
+				// Function values may only be compared to nil.
+				xCode := fn.cur.NewExtractValue(x, 0)
+				addMetadata(xCode, "field", "code")
+				yCode := fn.cur.NewExtractValue(y, 0)
+				addMetadata(yCode, "field", "code")
+				inst = fn.cur.NewICmp(irenum.IPredEQ, xCode, yCode)
+			case isComplexType(goType):
+				inst = fn.emitComplexEqual(x, y)
+			case isStringType(goType):
+				strequal := fn.m.getPredeclaredFunc("runtime.strequal")
+				inst = fn.cur.NewCall(strequal, x, y)
 			default:
 				panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 			}
@@ -508,18 +490,30 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 		case *irtypes.IntType:
 			inst = fn.cur.NewICmp(irenum.IPredNE, x, y)
 		case *irtypes.FloatType:
-			// TODO: figure out when to use FPredONE vs. FPredUNE (ordered vs.
-			// unordered).
-			inst = fn.cur.NewFCmp(irenum.FPredONE, x, y)
+			// Unordered comparison; NaN is not equal to any value, including NaN.
+			inst = fn.cur.NewFCmp(irenum.FPredUNE, x, y)
+		case *irtypes.PointerType:
+			// Pointers and maps.
+			inst = fn.cur.NewICmp(irenum.IPredNE, x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "complex64", "complex128":
-				panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
-			case "string":
-				cmp := fn.m.getPredeclaredFunc("cmp.string")
-				result := fn.cur.NewCall(cmp, x, y)
-				zero := irconstant.NewInt(result.Type().(*irtypes.IntType), 0)
-				inst = fn.cur.NewICmp(irenum.IPredNE, result, zero)
+			switch {
+			case gotypes.IsInterface(goType):
+				equal := fn.emitInterfaceEqual(x, y)
+				inst = fn.cur.NewXor(equal, irconstant.True)
+			case isFuncType(goType):
+				// Function values may only be compared to nil.
+				xCode := fn.cur.NewExtractValue(x, 0)
+				addMetadata(xCode, "field", "code")
+				yCode := fn.cur.NewExtractValue(y, 0)
+				addMetadata(yCode, "field", "code")
+				inst = fn.cur.NewICmp(irenum.IPredNE, xCode, yCode)
+			case isComplexType(goType):
+				equal := fn.emitComplexEqual(x, y)
+				inst = fn.cur.NewXor(equal, irconstant.True)
+			case isStringType(goType):
+				strequal := fn.m.getPredeclaredFunc("runtime.strequal")
+				equal := fn.cur.NewCall(strequal, x, y)
+				inst = fn.cur.NewXor(equal, irconstant.True)
 			default:
 				panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 			}
@@ -530,18 +524,17 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 	case token.LSS: // <
 		switch typ := x.Type().(type) {
 		case *irtypes.IntType:
-			if fn.m.isSigned(typ) {
+			if isSigned(goType) {
 				inst = fn.cur.NewICmp(irenum.IPredSLT, x, y)
 			} else {
 				inst = fn.cur.NewICmp(irenum.IPredULT, x, y)
 			}
 		case *irtypes.FloatType:
-			// TODO: figure out when to use FPredOLT vs. FPredULT (ordered vs.
-			// unordered).
+			// Ordered comparison; false if either operand is NaN.
 			inst = fn.cur.NewFCmp(irenum.FPredOLT, x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "string":
+			switch {
+			case isStringType(goType):
 				cmp := fn.m.getPredeclaredFunc("cmp.string")
 				result := fn.cur.NewCall(cmp, x, y)
 				zero := irconstant.NewInt(result.Type().(*irtypes.IntType), -1)
@@ -556,18 +549,17 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 	case token.LEQ: // <=
 		switch typ := x.Type().(type) {
 		case *irtypes.IntType:
-			if fn.m.isSigned(typ) {
+			if isSigned(goType) {
 				inst = fn.cur.NewICmp(irenum.IPredSLE, x, y)
 			} else {
 				inst = fn.cur.NewICmp(irenum.IPredULE, x, y)
 			}
 		case *irtypes.FloatType:
-			// TODO: figure out when to use FPredOLE vs. FPredULE (ordered vs.
-			// unordered).
+			// Ordered comparison; false if either operand is NaN.
 			inst = fn.cur.NewFCmp(irenum.FPredOLE, x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "string":
+			switch {
+			case isStringType(goType):
 				cmp := fn.m.getPredeclaredFunc("cmp.string")
 				result := fn.cur.NewCall(cmp, x, y)
 				zero := irconstant.NewInt(result.Type().(*irtypes.IntType), 1)
@@ -582,18 +574,17 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 	case token.GTR: // >
 		switch typ := x.Type().(type) {
 		case *irtypes.IntType:
-			if fn.m.isSigned(typ) {
+			if isSigned(goType) {
 				inst = fn.cur.NewICmp(irenum.IPredSGT, x, y)
 			} else {
 				inst = fn.cur.NewICmp(irenum.IPredUGT, x, y)
 			}
 		case *irtypes.FloatType:
-			// TODO: figure out when to use FPredOGT vs. FPredUGT (ordered vs.
-			// unordered).
+			// Ordered comparison; false if either operand is NaN.
 			inst = fn.cur.NewFCmp(irenum.FPredOGT, x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "string":
+			switch {
+			case isStringType(goType):
 				cmp := fn.m.getPredeclaredFunc("cmp.string")
 				result := fn.cur.NewCall(cmp, x, y)
 				zero := irconstant.NewInt(result.Type().(*irtypes.IntType), 1)
@@ -608,18 +599,17 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 	case token.GEQ: // >=
 		switch typ := x.Type().(type) {
 		case *irtypes.IntType:
-			if fn.m.isSigned(typ) {
+			if isSigned(goType) {
 				inst = fn.cur.NewICmp(irenum.IPredSGE, x, y)
 			} else {
 				inst = fn.cur.NewICmp(irenum.IPredUGE, x, y)
 			}
 		case *irtypes.FloatType:
-			// TODO: figure out when to use FPredOGE vs. FPredUGE (ordered vs.
-			// unordered).
+			// Ordered comparison; false if either operand is NaN.
 			inst = fn.cur.NewFCmp(irenum.FPredOGE, x, y)
 		case *irtypes.StructType:
-			switch typ.Name() {
-			case "string":
+			switch {
+			case isStringType(goType):
 				cmp := fn.m.getPredeclaredFunc("cmp.string")
 				result := fn.cur.NewCall(cmp, x, y)
 				zero := irconstant.NewInt(result.Type().(*irtypes.IntType), -1)
@@ -636,7 +626,7 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 	inst.SetName(goInst.Name())
 	// Add binary operation token metadata attachment to instruction.
 	addMetadata(inst, "binary_op", goInst.Op.String())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
 	dbg.Println("   inst:", inst.LLString())
 	return nil
 }
@@ -644,9 +634,10 @@ func (fn *Func) emitBinOp(goInst *ssa.BinOp) error {
 // ~~~ [ complex binary operation ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // emitComplexBinOp compiles the given complex binary operation to corresponding
-// LLVM IR instructions, emitting to fn.
+// LLVM IR instructions, emitting to fn. The operation is applied to the real and
+// imaginary parts separately (e.g. for addition and subtraction).
 func (fn *Func) emitComplexBinOp(op func(a, b irvalue.Value) irValueInstruction, x, y irvalue.Value) irValueInstruction {
-	typ := x.Type()
+	typ := x.Type().(*irtypes.StructType)
 	// real part.
 	xReal := fn.cur.NewExtractValue(x, 0)
 	addMetadata(xReal, "comment", "real")
@@ -668,13 +659,8 @@ func (fn *Func) emitComplexBinOp(op func(a, b irvalue.Value) irValueInstruction,
 	addMetadata(imag, "comment", "imag")
 	dbg.Println("   imag:", imag.LLString())
 	// result.
-	alloca := fn.cur.NewAlloca(typ)
-	result := fn.cur.NewLoad(typ, alloca)
-	tmp1 := fn.cur.NewInsertValue(result, real, 0)
-	dbg.Println("   tmp1:", tmp1.LLString())
-	tmp2 := fn.cur.NewInsertValue(result, imag, 1)
-	dbg.Println("   tmp2:", tmp2.LLString())
-	inst := tmp2
+	inst := fn.emitComplex(typ, real, imag)
+	dbg.Println("   inst:", inst.LLString())
 	return inst
 }
 
@@ -693,21 +679,66 @@ func (fn *Func) emitCall(goInst *ssa.Call) error {
 	}
 	// Receiver (invoke mode) or func value (call mode).
 	var callee irvalue.Value
-	if goCallee, ok := goInst.Call.Value.(*ssa.Builtin); ok {
-		// Synthesize generic builtin `len` function based on argument type.
-		switch goCallee.Name() {
-		// TODO: add support for more synthesized functions.
-		//case "cap":
-		case "len":
-			callee = fn.m.synthLen(args[0].Type())
-		}
-	}
-	if callee == nil {
-		callee = fn.useValue(goInst.Call.Value)
-	}
 	if goInst.Call.Method != nil {
-		// Receiver mode.
-		panic("support for receiver mode (method invocation) of Go SSA call instruction not yet implemented")
+		// Receiver mode; dynamic dispatch through interface method table.
+		var recv irvalue.Value
+		callee, recv = fn.emitInvokeCallee(&goInst.Call)
+		args = append([]irvalue.Value{recv}, args...)
+	} else {
+		switch goCallee := goInst.Call.Value.(type) {
+		case *ssa.Builtin:
+			// Synthesize generic builtin `len` function based on argument type.
+			switch goCallee.Name() {
+			case "print", "println":
+				// Pass values of defined string types as strings.
+				stringType := fn.m.irTypeFromName("string")
+				for i, goArg := range goInst.Call.Args {
+					if isStringType(goArg.Type()) {
+						args[i] = fn.cast(args[i], stringType)
+					}
+				}
+				callee = fn.useValue(goCallee)
+			case "append":
+				return fn.emitAppend(goInst, args)
+			case "cap":
+				callee = fn.m.synthCap(goInst.Call.Args[0].Type())
+			case "copy":
+				return fn.emitCopy(goInst, args)
+			case "len":
+				if isMapType(goInst.Call.Args[0].Type()) {
+					callee = fn.m.getPredeclaredFunc("runtime.maplen")
+					break
+				}
+				callee = fn.m.synthLen(goInst.Call.Args[0].Type())
+			case "complex":
+				return fn.emitComplexBuiltin(goInst, args)
+			case "real", "imag":
+				return fn.emitRealImag(goInst, args[0])
+			case "recover":
+				callee = fn.m.getPredeclaredFunc("runtime.gorecover")
+				args = append(args, fn.useRecoverPanic())
+			case "delete":
+				inst := fn.emitMapDelete(args[0], args[1])
+				dbg.Println("   inst:", inst.LLString())
+				return nil
+			default:
+				callee = fn.useValue(goCallee)
+			}
+		case *ssa.Function:
+			if goCallee.Pkg != nil && goCallee.Pkg.Pkg == gotypes.Unsafe {
+				// The unsafe package is never compiled, as it has no Go source;
+				// omit calls to its (empty) package initializer.
+				return nil
+			}
+			// Static function call.
+			callee = fn.m.irValueFromGoFunc(goCallee)
+		default:
+			// Dynamic function call through function value; pass context pointer
+			// as first argument.
+			var ctx irvalue.Value
+			callee, ctx = fn.emitFuncValueCallee(fn.useValue(goCallee))
+			args = append([]irvalue.Value{ctx}, args...)
+		}
 	}
 	dbg.Println("   callee:", callee.Ident())
 	// Bitcast pointer types of "ssa:wrapnilchk" call as follows.
@@ -733,7 +764,7 @@ func (fn *Func) emitCall(goInst *ssa.Call) error {
 	}
 	if !irtypes.Equal(inst.Type(), irtypes.Void) {
 		inst.SetName(goInst.Name())
-		fn.locals[goInst] = inst
+		fn.setLocal(goInst, inst)
 	}
 	dbg.Println("   inst:", inst.LLString())
 	return nil
@@ -743,17 +774,53 @@ func (fn *Func) emitCall(goInst *ssa.Call) error {
 
 // emitChangeInterface compiles the given Go SSA changeinterface instruction to
 // corresponding LLVM IR instructions, emitting to fn.
+//
+// The interface method table of the target interface type is located at run
+// time, based on the dynamic type of the interface value. Nil interface values
+// are converted to nil interface values.
 func (fn *Func) emitChangeInterface(goInst *ssa.ChangeInterface) error {
 	dbg.Println("emitChangeInterface")
 	x := fn.useValue(goInst.X)
-	toType := fn.m.irTypeFromGo(goInst.Type())
-	switch xType := x.Type().(type) {
-	case *irtypes.StructType:
-		_ = toType
-		panic(fmt.Errorf("support for type %T (%q) in changeinterface instruction not yet implemented", xType, xType.Name()))
-	default:
-		panic(fmt.Errorf("support for type %T (%q) in changeinterface instruction not yet implemented", xType, xType.Name()))
+	tab := fn.cur.NewExtractValue(x, 0)
+	addMetadata(tab, "field", "tab")
+	data := fn.cur.NewExtractValue(x, 1)
+	addMetadata(data, "field", "data")
+	// The conversion is statically known to succeed for non-nil interface
+	// values; use the comma-ok form of interface type assertions to handle nil
+	// interface values without panicking.
+	v, _ := fn.emitAssertIface(tab, data, goInst.Type(), true)
+	inst := v.(irValueInstruction)
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ changetype instruction ] ----------------------------------------------
+
+// emitChangeType compiles the given Go SSA changetype instruction to
+// corresponding LLVM IR instructions, emitting to fn.
+//
+// Type changes between types of identical underlying types (e.g. from []int to
+// `type IDs []int`) preserve the value, and are thus only reflected in the LLVM
+// IR type of the value.
+func (fn *Func) emitChangeType(goInst *ssa.ChangeType) error {
+	dbg.Println("emitChangeType")
+	x := fn.useValue(goInst.X)
+	typ := fn.m.irTypeFromGo(goInst.Type())
+	v := fn.cast(x, typ)
+	if v == x {
+		// No-op type change (e.g. from int to `type Celsius int`); reuse the
+		// LLVM IR value of x.
+		fn.setLocal(goInst, v)
+		dbg.Println("   v:", v.Ident())
+		return nil
 	}
+	inst := v.(irValueInstruction)
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
 }
 
 // --- [ convert instruction ] -------------------------------------------------
@@ -771,10 +838,16 @@ func (fn *Func) emitChangeInterface(goInst *ssa.ChangeInterface) error {
 func (fn *Func) emitConvert(goInst *ssa.Convert) error {
 	dbg.Println("emitConvert")
 	from := fn.useValue(goInst.X)
-	to := fn.m.irTypeFromGo(goInst.Type())
-	inst := fn.convert(from, to)
+	goFrom := goInst.X.Type()
+	var inst irValueInstruction
+	if isStringType(goFrom) || isStringType(goInst.Type()) {
+		// Conversions to and from string.
+		inst = fn.emitStringConvert(from, goFrom, goInst.Type())
+	} else {
+		inst = fn.convert(from, goFrom, goInst.Type())
+	}
 	inst.SetName(goInst.Name())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
 	dbg.Println("   inst:", inst.LLString())
 	return nil
 }
@@ -788,7 +861,28 @@ func (fn *Func) emitExtract(goInst *ssa.Extract) error {
 	tuple := fn.useValue(goInst.Tuple)
 	inst := fn.cur.NewExtractValue(tuple, uint64(goInst.Index))
 	inst.SetName(goInst.Name())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ field instruction ] ---------------------------------------------------
+
+// emitField compiles the given Go SSA field instruction to corresponding LLVM
+// IR instructions, emitting to fn.
+func (fn *Func) emitField(goInst *ssa.Field) error {
+	dbg.Println("emitField")
+	x := fn.useValue(goInst.X)
+	var inst irValueInstruction
+	switch xType := x.Type().(type) {
+	// struct
+	case *irtypes.StructType:
+		inst = fn.cur.NewExtractValue(x, uint64(goInst.Field))
+	default:
+		panic(fmt.Errorf("support for %T of field instruction not yet implemented", xType))
+	}
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
 	dbg.Println("   inst:", inst.LLString())
 	return nil
 }
@@ -822,7 +916,45 @@ func (fn *Func) emitFieldAddr(goInst *ssa.FieldAddr) error {
 		panic(fmt.Errorf("support for %T of fieldaddr instruction not yet implemented", xType))
 	}
 	inst.SetName(goInst.Name())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ index instruction ] ---------------------------------------------------
+
+// emitIndex compiles the given Go SSA index instruction to corresponding LLVM
+// IR instructions, emitting to fn.
+func (fn *Func) emitIndex(goInst *ssa.Index) error {
+	dbg.Println("emitIndex")
+	x := fn.useValue(goInst.X)
+	index, signed := fn.emitIntIndex(goInst.Index)
+	var inst irValueInstruction
+	switch xType := x.Type().(type) {
+	// array
+	case *irtypes.ArrayType:
+		length := irconstant.NewInt(fn.m.irTypeFromName("int").(*irtypes.IntType), int64(xType.Len))
+		fn.emitBoundsCheck(index, signed, length, boundsIndex)
+		if c, ok := index.(*irconstant.Int); ok {
+			// Constant index.
+			inst = fn.cur.NewExtractValue(x, c.X.Uint64())
+			break
+		}
+		// Dynamic index; spill array to stack slot to index into it.
+		slot := fn.entry.NewAlloca(xType)
+		fn.cur.NewStore(x, slot)
+		zero := irconstant.NewInt(irtypes.I64, 0)
+		indices := []irvalue.Value{
+			zero,
+			index,
+		}
+		elemPtr := fn.cur.NewGetElementPtr(xType, slot, indices...)
+		inst = fn.cur.NewLoad(xType.ElemType, elemPtr)
+	default:
+		panic(fmt.Errorf("support for %T of index instruction not yet implemented", xType))
+	}
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
 	dbg.Println("   inst:", inst.LLString())
 	return nil
 }
@@ -834,12 +966,19 @@ func (fn *Func) emitFieldAddr(goInst *ssa.FieldAddr) error {
 func (fn *Func) emitIndexAddr(goInst *ssa.IndexAddr) error {
 	dbg.Println("emitIndexAddr")
 	x := fn.useValue(goInst.X)
-	index := fn.useValue(goInst.Index)
+	goXType := goInst.X.Type()
+	if isPointerType(goXType) {
+		x = fn.emitElemPtr(x, goXType)
+	}
+	index, signed := fn.emitIntIndex(goInst.Index)
 	var inst irValueInstruction
 	switch xType := x.Type().(type) {
 	// *array
 	case *irtypes.PointerType:
 		// TODO: Verify that index into arrays are working as intended.
+		arrayType := xType.ElemType.(*irtypes.ArrayType)
+		length := irconstant.NewInt(fn.m.irTypeFromName("int").(*irtypes.IntType), int64(arrayType.Len))
+		fn.emitBoundsCheck(index, signed, length, boundsIndex)
 		zero := irconstant.NewInt(irtypes.I64, 0)
 		indices := []irvalue.Value{
 			zero,
@@ -849,18 +988,14 @@ func (fn *Func) emitIndexAddr(goInst *ssa.IndexAddr) error {
 	case *irtypes.StructType:
 		switch {
 		// slice
-		case strings.HasPrefix(xType.Name(), "[]"): // TODO: use other approach than type name to identify slice types; this approach is fragile as it doesn't handle type definitions (e.g. `type T []int`).
+		case isSliceType(goXType):
 			dataType := xType.Fields[0].(*irtypes.PointerType)
 			data := fn.cur.NewExtractValue(x, 0)
 			addMetadata(data, "field", "data")
 			dbg.Println("   data:", data.LLString())
 			length := fn.cur.NewExtractValue(x, 1)
 			addMetadata(length, "field", "len")
-			capacity := fn.cur.NewExtractValue(x, 2)
-			addMetadata(capacity, "field", "cap")
-			// TODO: add bounds check of index against 0, len and cap.
-			//
-			//    0 <= index <= len <= cap
+			fn.emitBoundsCheck(index, signed, length, boundsIndex)
 			// TODO: Verify that index into slices are working as intended.
 			inst = fn.cur.NewGetElementPtr(dataType.ElemType, data, index)
 		default:
@@ -870,7 +1005,7 @@ func (fn *Func) emitIndexAddr(goInst *ssa.IndexAddr) error {
 		panic(fmt.Errorf("support for %T of indexaddr instruction not yet implemented", xType))
 	}
 	inst.SetName(goInst.Name())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
 	dbg.Println("   inst:", inst.LLString())
 	return nil
 }
@@ -882,25 +1017,41 @@ func (fn *Func) emitIndexAddr(goInst *ssa.IndexAddr) error {
 func (fn *Func) emitLookup(goInst *ssa.Lookup) error {
 	dbg.Println("emitLookup")
 	x := fn.useValue(goInst.X)
+	goXType := goInst.X.Type()
 	index := fn.useValue(goInst.Index)
 	var inst irValueInstruction
+	if goMapType, ok := goXType.Underlying().(*gotypes.Map); ok {
+		// map
+		v, ok := fn.emitMapAccess(x, index, goMapType)
+		if goInst.CommaOk {
+			// The result is a 2-tuple of the element and a boolean indicating
+			// whether the key was present.
+			tupleType := fn.m.irTypeFromGo(goInst.Type())
+			insertValue := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(tupleType), v, 0)
+			inst = fn.cur.NewInsertValue(insertValue, ok, 1)
+		} else {
+			inst = v.(irValueInstruction)
+		}
+		inst.SetName(goInst.Name())
+		fn.setLocal(goInst, inst)
+		dbg.Println("   inst:", inst.LLString())
+		return nil
+	}
 	switch xType := x.Type().(type) {
 	case *irtypes.StructType:
 		switch {
 		// string
-		case xType.Name() == "string":
+		case isStringType(goXType):
 			dataType := xType.Fields[0].(*irtypes.PointerType)
 			data := fn.cur.NewExtractValue(x, 0)
 			addMetadata(data, "field", "data")
+			length := fn.cur.NewExtractValue(x, 1)
+			addMetadata(length, "field", "len")
+			index, signed := fn.emitIntIndex(goInst.Index)
+			fn.emitBoundsCheck(index, signed, length, boundsIndex)
 			gep := fn.cur.NewGetElementPtr(dataType.ElemType, data, index)
 			dbg.Println("   gep:", gep.LLString())
 			inst = fn.cur.NewLoad(dataType.ElemType, gep)
-		// map
-		case strings.HasPrefix(xType.Name(), "map["): // TODO: use other approach than type name to identify map types; this approach is fragile as it doesn't handle type definitions (e.g. `type T map[int]string`).
-			if goInst.CommaOk {
-				// TODO: add support for `comma, ok` use of map.
-			}
-			panic(fmt.Errorf("support for type %T (%q) in lookup instruction not yet implemented", xType, xType.Name()))
 		default:
 			panic(fmt.Errorf("support for type %T (%q) in lookup instruction not yet implemented", xType, xType.Name()))
 		}
@@ -908,7 +1059,180 @@ func (fn *Func) emitLookup(goInst *ssa.Lookup) error {
 		panic(fmt.Errorf("support for type %T (%q) in lookup instruction not yet implemented", xType, xType.Name()))
 	}
 	inst.SetName(goInst.Name())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ makeclosure instruction ] ---------------------------------------------
+
+// emitMakeClosure compiles the given Go SSA makeclosure instruction to
+// corresponding LLVM IR instructions, emitting to fn.
+//
+// The values of the bindings are copied to a context allocated on the heap,
+// which is passed to the closure as first argument when invoked through the
+// function value.
+func (fn *Func) emitMakeClosure(goInst *ssa.MakeClosure) error {
+	dbg.Println("emitMakeClosure")
+	goFunc := goInst.Fn.(*ssa.Function)
+	f := fn.m.irValueFromGoFunc(goFunc)
+	typ := fn.m.irTypeFromGo(goInst.Type()).(*irtypes.StructType)
+	// Context of closure.
+	newFunc := fn.m.synthNew(closureContextType(goFunc))
+	ctxPtr := fn.cur.NewCall(newFunc)
+	ctxType := ctxPtr.Type().(*irtypes.PointerType).ElemType
+	zero := irconstant.NewInt(irtypes.I64, 0)
+	for i, goBinding := range goInst.Bindings {
+		binding := fn.useValue(goBinding)
+		indices := []irvalue.Value{
+			zero,
+			irconstant.NewInt(irtypes.I32, int64(i)),
+		}
+		ptr := fn.cur.NewGetElementPtr(ctxType, ctxPtr, indices...)
+		fn.cur.NewStore(binding, ptr)
+	}
+	ctx := fn.cur.NewBitCast(ctxPtr, irtypes.I8Ptr)
+	// Code pointer of closure.
+	var code irvalue.Value = f
+	if !irtypes.Equal(f.Type(), typ.Fields[0]) {
+		code = irconstant.NewBitCast(f, typ.Fields[0])
+	}
+	insertCode := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(typ), code, 0)
+	addMetadata(insertCode, "field", "code")
+	inst := fn.cur.NewInsertValue(insertCode, ctx, 1)
+	addMetadata(inst, "field", "ctx")
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ makeinterface instruction ] -------------------------------------------
+
+// emitMakeInterface compiles the given Go SSA makeinterface instruction to
+// corresponding LLVM IR instructions, emitting to fn.
+func (fn *Func) emitMakeInterface(goInst *ssa.MakeInterface) error {
+	dbg.Println("emitMakeInterface")
+	x := fn.useValue(goInst.X)
+	typ := fn.m.irTypeFromGo(goInst.Type()).(*irtypes.StructType)
+	// Interface method table of concrete type and interface type.
+	itab := fn.m.getItab(goInst.X.Type(), goInst.Type())
+	tab := irconstant.NewBitCast(itab, typ.Fields[0])
+	// Data pointer.
+	data := fn.emitBox(x, goInst.X.Type())
+	insertTab := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(typ), tab, 0)
+	addMetadata(insertTab, "field", "tab")
+	inst := fn.cur.NewInsertValue(insertTab, data, 1)
+	addMetadata(inst, "field", "data")
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ makemap instruction ] -------------------------------------------------
+
+// emitMakeMap compiles the given Go SSA makemap instruction to corresponding
+// LLVM IR instructions, emitting to fn.
+func (fn *Func) emitMakeMap(goInst *ssa.MakeMap) error {
+	dbg.Println("emitMakeMap")
+	goMapType := goInst.Type().Underlying().(*gotypes.Map)
+	key := fn.m.getTypeDesc(goMapType.Key())
+	elem := fn.m.getTypeDesc(goMapType.Elem())
+	intType := fn.m.irTypeFromName("int").(*irtypes.IntType)
+	var hint irvalue.Value = irconstant.NewInt(intType, 0)
+	if goInst.Reserve != nil {
+		hint = fn.useValue(goInst.Reserve)
+		if !irtypes.Equal(hint.Type(), intType) {
+			hint = fn.convert(hint, goInst.Reserve.Type(), gotypes.Typ[gotypes.Int])
+		}
+	}
+	makemap := fn.m.getPredeclaredFunc("runtime.makemap")
+	h := fn.cur.NewCall(makemap, key, elem, hint)
+	// Both the call and the cast of its result to a defined map type are
+	// instructions.
+	inst := fn.cast(h, fn.m.irTypeFromGo(goInst.Type())).(irValueInstruction)
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ makeslice instruction ] -----------------------------------------------
+
+// emitMakeSlice compiles the given Go SSA makeslice instruction to
+// corresponding LLVM IR instructions, emitting to fn.
+func (fn *Func) emitMakeSlice(goInst *ssa.MakeSlice) error {
+	dbg.Println("emitMakeSlice")
+	typ := fn.m.irTypeFromGo(goInst.Type()).(*irtypes.StructType)
+	dataType := typ.Fields[0].(*irtypes.PointerType)
+	intType := fn.m.irTypeFromName("int")
+	goIntType := gotypes.Typ[gotypes.Int]
+	length := fn.useValue(goInst.Len)
+	if !irtypes.Equal(length.Type(), intType) {
+		length = fn.convert(length, goInst.Len.Type(), goIntType)
+	}
+	capacity := fn.useValue(goInst.Cap)
+	if !irtypes.Equal(capacity.Type(), intType) {
+		capacity = fn.convert(capacity, goInst.Cap.Type(), goIntType)
+	}
+	makeslice := fn.m.getPredeclaredFunc("runtime.makeslice")
+	p := fn.cur.NewCall(makeslice, fn.m.sizeof(dataType.ElemType), length, capacity)
+	data := fn.cur.NewBitCast(p, dataType)
+	slice := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(typ), data, 0)
+	slice = fn.cur.NewInsertValue(slice, length, 1)
+	inst := fn.cur.NewInsertValue(slice, capacity, 2)
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ next instruction ] ----------------------------------------------------
+
+// emitNext compiles the given Go SSA next instruction to corresponding LLVM IR
+// instructions, emitting to fn.
+//
+// The result is a 3-tuple of a boolean indicating whether an element was
+// produced, and the key and value of the element. The key and value are only
+// present in the tuple if used by the range statement; otherwise, their
+// respective fields are of empty struct type.
+func (fn *Func) emitNext(goInst *ssa.Next) error {
+	dbg.Println("emitNext")
+	iter := fn.useValue(goInst.Iter)
+	goTuple := goInst.Type().(*gotypes.Tuple)
+	goKeyType, goElemType := goTuple.At(1).Type(), goTuple.At(2).Type()
+	var ok, k, v irvalue.Value
+	if goInst.IsString {
+		ok, k, v = fn.emitStringIterNext(iter)
+		// Drop unused key and value.
+		if !isValidType(goKeyType) {
+			k = nil
+		}
+		if !isValidType(goElemType) {
+			v = nil
+		}
+	} else {
+		ok, k, v = fn.emitMapIterNext(iter, goKeyType, goElemType)
+	}
+	// Use empty struct type for unused key and value.
+	fields := []irtypes.Type{ok.Type(), irtypes.NewStruct(), irtypes.NewStruct()}
+	if k != nil {
+		fields[1] = k.Type()
+	}
+	if v != nil {
+		fields[2] = v.Type()
+	}
+	tupleType := irtypes.NewStruct(fields...)
+	inst := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(tupleType), ok, 0)
+	if k != nil {
+		inst = fn.cur.NewInsertValue(inst, k, 1)
+	}
+	if v != nil {
+		inst = fn.cur.NewInsertValue(inst, v, 2)
+	}
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
 	dbg.Println("   inst:", inst.LLString())
 	return nil
 }
@@ -923,7 +1247,7 @@ func (fn *Func) emitPhi(goInst *ssa.Phi) error {
 	for i, goEdge := range goInst.Edges {
 		x := fn.useValue(goEdge)
 		goPred := goInst.Block().Preds[i]
-		pred := fn.getBlock(goPred)
+		pred := fn.getExitBlock(goPred)
 		inc := ir.NewIncoming(x, pred)
 		incs = append(incs, inc)
 	}
@@ -932,11 +1256,36 @@ func (fn *Func) emitPhi(goInst *ssa.Phi) error {
 		addMetadata(inst, "comment", goInst.Comment)
 	}
 	inst.SetName(goInst.Name())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
 	dbg.Println("   inst:", inst.LLString())
 	return nil
 }
 
+// --- [ range instruction ] ---------------------------------------------------
+
+// emitRange compiles the given Go SSA range instruction to corresponding LLVM
+// IR instructions, emitting to fn.
+//
+// The result is an iterator, which is advanced by next instructions.
+func (fn *Func) emitRange(goInst *ssa.Range) error {
+	dbg.Println("emitRange")
+	x := fn.useValue(goInst.X)
+	goXType := goInst.X.Type()
+	var inst irvalue.Value
+	switch {
+	case isStringType(goXType):
+		inst = fn.emitStringIterInit(x)
+	case isMapType(goXType):
+		mapiterinit := fn.m.getPredeclaredFunc("runtime.mapiterinit")
+		inst = fn.cur.NewCall(mapiterinit, x)
+	default:
+		panic(fmt.Errorf("support for range over type %v (in %q) not yet implemented", goXType, goInst.Name()))
+	}
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.Ident())
+	return nil
+}
+
 // --- [ slice instruction ] ---------------------------------------------------
 
 // emitSlice compiles the given Go SSA slice instruction to corresponding LLVM
@@ -944,16 +1293,21 @@ func (fn *Func) emitPhi(goInst *ssa.Phi) error {
 func (fn *Func) emitSlice(goInst *ssa.Slice) error {
 	dbg.Println("emitSlice")
 	x := fn.useValue(goInst.X) // slice, string, or *array
+	goXType := goInst.X.Type()
+	if isPointerType(goXType) {
+		x = fn.emitElemPtr(x, goXType)
+	}
 	dbg.Println("   x:", x.String())
 	var low, high, max irvalue.Value
+	var lowSigned, highSigned, maxSigned bool
 	if goInst.Low != nil {
-		low = fn.useValue(goInst.Low)
+		low, lowSigned = fn.emitIntIndex(goInst.Low)
 	}
 	if goInst.High != nil {
-		high = fn.useValue(goInst.High)
+		high, highSigned = fn.emitIntIndex(goInst.High)
 	}
 	if goInst.Max != nil {
-		max = fn.useValue(goInst.Max)
+		max, maxSigned = fn.emitIntIndex(goInst.Max)
 	}
 	// Get element type.
 	var elemType irtypes.Type
@@ -963,12 +1317,14 @@ func (fn *Func) emitSlice(goInst *ssa.Slice) error {
 		capacity irvalue.Value
 	)
 	isString := false
+	isSlice := false
 	switch xType := x.Type().(type) {
 	// slice, string
 	case *irtypes.StructType:
 		switch {
 		// slice
-		case strings.HasPrefix(xType.Name(), "[]"): // TODO: use other approach than type name to identify slice types; this approach is fragile as it doesn't handle type definitions (e.g. `type T []int`).
+		case isSliceType(goXType):
+			isSlice = true
 			dataType := xType.Fields[0].(*irtypes.PointerType)
 			elemType = dataType.ElemType
 			dataField := fn.cur.NewExtractValue(x, 0)
@@ -981,7 +1337,7 @@ func (fn *Func) emitSlice(goInst *ssa.Slice) error {
 			addMetadata(capacityField, "field", "cap")
 			capacity = capacityField
 		// string
-		case xType.Name() == "string":
+		case isStringType(goXType):
 			isString = true
 			elemType = fn.m.irTypeFromName("uint8") // TODO: use byte alias instead of uint8.
 			dataField := fn.cur.NewExtractValue(x, 0)
@@ -1015,30 +1371,59 @@ func (fn *Func) emitSlice(goInst *ssa.Slice) error {
 		panic(fmt.Errorf("support for type %T in slice instruction not yet implemented", xType))
 	}
 	// Allocate new slice value.
-	var sliceType irtypes.Type
-	if isString {
-		sliceType = fn.m.irTypeFromName("string")
-	} else {
-		sliceType = fn.m.newSliceType(elemType)
-	}
+	sliceType := fn.m.irTypeFromGo(goInst.Type())
 	alloca := fn.cur.NewAlloca(sliceType)
 	fn.cur.NewStore(irconstant.NewZeroInitializer(sliceType), alloca)
 	var slice irvalue.Value = fn.cur.NewLoad(sliceType, alloca)
-	// TODO: add bounds check of low, high and max.
+	// Bounds check indices in reverse order, so that each index is compared
+	// against a value known to be non-negative.
+	//
+	//    0 <= low <= high <= max <= cap
+	if max != nil {
+		// x[low:high:max]
+		kind := boundsSlice3Alen
+		if isSlice {
+			kind = boundsSlice3Acap
+		}
+		fn.emitBoundsCheck(max, maxSigned, capacity, kind)
+		fn.emitBoundsCheck(high, highSigned, max, boundsSlice3B)
+		if low != nil {
+			fn.emitBoundsCheck(low, lowSigned, high, boundsSlice3C)
+		}
+	} else {
+		// x[low:high]
+		end := length
+		if high != nil {
+			kind := boundsSliceAlen
+			if isSlice {
+				kind = boundsSliceAcap
+			}
+			fn.emitBoundsCheck(high, highSigned, capacity, kind)
+			end = high
+		}
+		if low != nil {
+			fn.emitBoundsCheck(low, lowSigned, end, boundsSliceB)
+		}
+	}
 	// data[low::]
 	if low != nil {
 		data = fn.cur.NewGetElementPtr(elemType, data, low)
 	}
+	if dataType := sliceType.(*irtypes.StructType).Fields[0]; data.Type().LLString() != dataType.LLString() {
+		// Elements of arrays referring to their own type may be of placeholder
+		// type (see irTypeFromGo).
+		data = fn.cur.NewBitCast(data, dataType)
+	}
 	insertData := fn.cur.NewInsertValue(slice, data, 0)
 	addMetadata(insertData, "field", "data")
 	slice = insertData
 	// data[:high:]
 	if high != nil {
-		if low != nil {
-			high = fn.cur.NewSub(high, low)
-		}
 		length = high
 	}
+	if low != nil {
+		length = fn.cur.NewSub(length, low)
+	}
 	insertLength := fn.cur.NewInsertValue(slice, length, 1)
 	addMetadata(insertLength, "field", "len")
 	slice = insertLength
@@ -1049,17 +1434,50 @@ func (fn *Func) emitSlice(goInst *ssa.Slice) error {
 	} else {
 		// data[::max]
 		if max != nil {
-			if low != nil {
-				max = fn.cur.NewSub(max, low)
-			}
 			capacity = max
 		}
+		if low != nil {
+			capacity = fn.cur.NewSub(capacity, low)
+		}
 		insertCapacity := fn.cur.NewInsertValue(slice, capacity, 2)
 		addMetadata(insertCapacity, "field", "cap")
 		inst = insertCapacity
 	}
 	inst.SetName(goInst.Name())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ typeassert instruction ] ----------------------------------------------
+
+// emitTypeAssert compiles the given Go SSA typeassert instruction to
+// corresponding LLVM IR instructions, emitting to fn.
+func (fn *Func) emitTypeAssert(goInst *ssa.TypeAssert) error {
+	dbg.Println("emitTypeAssert")
+	x := fn.useValue(goInst.X)
+	tab := fn.cur.NewExtractValue(x, 0)
+	addMetadata(tab, "field", "tab")
+	data := fn.cur.NewExtractValue(x, 1)
+	addMetadata(data, "field", "data")
+	var v, ok irvalue.Value
+	if gotypes.IsInterface(goInst.AssertedType) {
+		v, ok = fn.emitAssertIface(tab, data, goInst.AssertedType, goInst.CommaOk)
+	} else {
+		v, ok = fn.emitAssertConcrete(tab, data, goInst.X.Type(), goInst.AssertedType, goInst.CommaOk)
+	}
+	var inst irValueInstruction
+	if goInst.CommaOk {
+		// The result is a 2-tuple of the value and a boolean indicating the
+		// success of the type assertion.
+		tupleType := fn.m.irTypeFromGo(goInst.Type())
+		insertValue := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(tupleType), v, 0)
+		inst = fn.cur.NewInsertValue(insertValue, ok, 1)
+	} else {
+		inst = v.(irValueInstruction)
+	}
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
 	dbg.Println("   inst:", inst.LLString())
 	return nil
 }
@@ -1087,8 +1505,13 @@ func (fn *Func) emitUnOp(goInst *ssa.UnOp) error {
 			zero := irconstant.NewInt(typ, 0)
 			inst = fn.cur.NewSub(zero, x)
 		case *irtypes.FloatType:
-			zero := irconstant.NewFloat(typ, 0)
-			inst = fn.cur.NewFSub(zero, x)
+			// Use fneg rather than fsub from 0, as -(+0) is -0.
+			inst = fn.cur.NewFNeg(x)
+		case *irtypes.StructType:
+			if !isComplexType(goInst.X.Type()) {
+				panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
+			}
+			inst = fn.emitComplexNeg(x)
 		default:
 			panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 		}
@@ -1103,14 +1526,16 @@ func (fn *Func) emitUnOp(goInst *ssa.UnOp) error {
 		panic("support for Go SSA unary operation instruction token ARROW (<-) not yet implemented")
 	// Pointer indirection (load).
 	case token.MUL: // *
+		x = fn.emitElemPtr(x, goInst.X.Type())
 		elemType := x.Type().(*irtypes.PointerType).ElemType
 		inst = fn.cur.NewLoad(elemType, x)
 	// Bitwise complement.
 	case token.XOR: // ^
 		switch typ := x.Type().(type) {
 		case *irtypes.IntType:
-			zero := irconstant.NewInt(typ, 0)
-			inst = fn.cur.NewXor(x, zero)
+			// Bitwise complement is xor with all bits set.
+			minusOne := irconstant.NewInt(typ, -1)
+			inst = fn.cur.NewXor(x, minusOne)
 		default:
 			panic(fmt.Errorf("support for operand type %T (%q) of Go SSA binary operation instruction (%v) not yet implemented", typ, typ.Name(), goInst.Op))
 		}
@@ -1120,7 +1545,7 @@ func (fn *Func) emitUnOp(goInst *ssa.UnOp) error {
 	inst.SetName(goInst.Name())
 	// Add unary operation token metadata attachment to instruction.
 	addMetadata(inst, "unary_op", goInst.Op.String())
-	fn.locals[goInst] = inst
+	fn.setLocal(goInst, inst)
 	dbg.Println("   inst:", inst.LLString())
 	return nil
 }
@@ -0,0 +1,385 @@
+package irgen
+
+import (
+	"fmt"
+	gotypes "go/types"
+	"strings"
+
+	"github.com/llir/llvm/ir"
+	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
+	irtypes "github.com/llir/llvm/ir/types"
+	irvalue "github.com/llir/llvm/ir/value"
+	"golang.org/x/tools/go/ssa"
+)
+
+// --- [ type descriptor ] -----------------------------------------------------
+
+// getTypeDesc returns the LLVM IR global variable holding the runtime type
+// descriptor of the given Go type, emitting to m. The type descriptor is
+// created if not already present.
+//
+// Type descriptors are uniqued by name, and may be emitted by several LLVM IR
+// modules. Thus, two interface values hold the same dynamic type if their type
+// descriptors have the same address.
+func (m *Module) getTypeDesc(goType gotypes.Type) *ir.Global {
+	typeDescName := "type:" + m.typeName(goType, nil)
+	if g, ok := m.typeDescs[typeDescName]; ok {
+		return g
+	}
+	typeDescType := m.irTypeFromName("runtime._type").(*irtypes.StructType)
+	// Index type descriptor before generating its contents, as the methods of
+	// the type may refer back to the type descriptor.
+	g := m.Module.NewGlobal(typeDescName, typeDescType)
+	g.Immutable = true
+	g.Linkage = irenum.LinkageLinkOnceODR
+	m.typeDescs[typeDescName] = g
+	// name field.
+	goStringType := gotypes.Typ[gotypes.String]
+	name := m.irValueFromGoStringLit(goStringType, typeString(goType))
+	// size field.
+	typ := m.irTypeFromGo(goType)
+	size := m.sizeof(typ)
+	// kind field.
+	kind := irconstant.NewInt(m.irTypeFromName("uint8").(*irtypes.IntType), int64(kindOf(goType)))
+	// equal field.
+	equalType := typeDescType.Fields[3].(*irtypes.PointerType)
+	var equal irconstant.Constant = irconstant.NewNull(equalType)
+	if equalFunc := m.synthEqual(goType); equalFunc != nil {
+		equal = equalFunc
+	}
+	// hash field.
+	hashType := typeDescType.Fields[4].(*irtypes.PointerType)
+	var hash irconstant.Constant = irconstant.NewNull(hashType)
+	if hashFunc := m.synthHash(goType); hashFunc != nil {
+		hash = hashFunc
+	}
+	// methods and nmethods fields.
+	methodType := m.irTypeFromName("runtime.method").(*irtypes.StructType)
+	var methods []irconstant.Constant
+	if goIfaceType, ok := goType.Underlying().(*gotypes.Interface); ok {
+		// Interface types record the methods required by the interface; without
+		// function pointers.
+		for i := 0; i < goIfaceType.NumMethods(); i++ {
+			goMethod := goIfaceType.Method(i)
+			methodName := m.irValueFromGoStringLit(goStringType, m.methodKey(goMethod))
+			method := irconstant.NewStruct(methodType, methodName, irconstant.NewNull(irtypes.I8Ptr))
+			methods = append(methods, method)
+		}
+	} else {
+		// Concrete types record the methods of the method set of the receiver
+		// type used for method invocation through interfaces.
+		goMethodSet := m.goPkg.Prog.MethodSets.MethodSet(recvTypeOf(goType))
+		for i := 0; i < goMethodSet.Len(); i++ {
+			goSel := goMethodSet.At(i)
+			goMethod := goSel.Obj().(*gotypes.Func)
+			methodName := m.irValueFromGoStringLit(goStringType, m.methodKey(goMethod))
+			f := m.irValueFromGoFunc(m.goPkg.Prog.MethodValue(goSel))
+			method := irconstant.NewStruct(methodType, methodName, irconstant.NewBitCast(f, irtypes.I8Ptr))
+			methods = append(methods, method)
+		}
+	}
+	nmethods := irconstant.NewInt(m.irTypeFromName("int").(*irtypes.IntType), int64(len(methods)))
+	var methodsPtr irconstant.Constant = irconstant.NewNull(irtypes.NewPointer(methodType))
+	if len(methods) > 0 {
+		methodsArray := irconstant.NewArray(irtypes.NewArray(uint64(len(methods)), methodType), methods...)
+		methodsGlobal := m.Module.NewGlobalDef(typeDescName+"$methods", methodsArray)
+		methodsGlobal.Immutable = true
+		methodsGlobal.Linkage = irenum.LinkageLinkOnceODR
+		zero := irconstant.NewInt(irtypes.I64, 0)
+		methodsPtr = irconstant.NewGetElementPtr(methodsArray.Typ, methodsGlobal, zero, zero)
+	}
+	g.Init = irconstant.NewStruct(typeDescType, name, size, kind, equal, hash, methodsPtr, nmethods)
+	return g
+}
+
+// --- [ interface method table ] ----------------------------------------------
+
+// getItab returns the LLVM IR global variable holding the interface method
+// table of the given concrete Go type and Go interface type, emitting to m.
+// The interface method table is created if not already present.
+//
+// The function pointers of the interface method table are stored in the order
+// of the methods of the interface type.
+func (m *Module) getItab(goConcreteType, goIfaceType gotypes.Type) *ir.Global {
+	itabName := fmt.Sprintf("itab:%s,%s", m.typeName(goConcreteType, nil), m.typeName(goIfaceType, nil))
+	if g, ok := m.itabs[itabName]; ok {
+		return g
+	}
+	inter := m.getTypeDesc(goIfaceType)
+	typ := m.getTypeDesc(goConcreteType)
+	goMethodSet := m.goPkg.Prog.MethodSets.MethodSet(recvTypeOf(goConcreteType))
+	iface := goIfaceType.Underlying().(*gotypes.Interface)
+	var funcs []irconstant.Constant
+	for i := 0; i < iface.NumMethods(); i++ {
+		goMethod := iface.Method(i)
+		goSel := goMethodSet.Lookup(goMethod.Pkg(), goMethod.Name())
+		if goSel == nil {
+			panic(fmt.Errorf("unable to locate method %q of interface type %q in method set of concrete type %q", goMethod.Name(), goIfaceType, goConcreteType))
+		}
+		f := m.irValueFromGoFunc(m.goPkg.Prog.MethodValue(goSel))
+		funcs = append(funcs, irconstant.NewBitCast(f, irtypes.I8Ptr))
+	}
+	funcsArray := irconstant.NewArray(irtypes.NewArray(uint64(len(funcs)), irtypes.I8Ptr), funcs...)
+	// The interface method table has a variable length array of function
+	// pointers, and is thus represented by a literal structure type; use
+	// bitcast to get a %runtime.itab pointer.
+	init := irconstant.NewStruct(irtypes.NewStruct(inter.Type(), typ.Type(), funcsArray.Type()), inter, typ, funcsArray)
+	g := m.Module.NewGlobalDef(itabName, init)
+	g.Immutable = true
+	g.Linkage = irenum.LinkageLinkOnceODR
+	m.itabs[itabName] = g
+	return g
+}
+
+// --- [ box ] -----------------------------------------------------------------
+
+// emitBox returns the data pointer of an interface value holding the given
+// value x of the specified Go type, emitting to fn.
+//
+// Values of pointer shaped types are stored directly in the data pointer of
+// interface values. Values of other types are copied to the heap, and the data
+// pointer points to the copy.
+func (fn *Func) emitBox(x irvalue.Value, goType gotypes.Type) irvalue.Value {
+	if isDirectIface(goType) {
+		return fn.cur.NewBitCast(x, irtypes.I8Ptr)
+	}
+	newFunc := fn.m.synthNew(goType)
+	ptr := fn.cur.NewCall(newFunc)
+	fn.cur.NewStore(x, ptr)
+	return fn.cur.NewBitCast(ptr, irtypes.I8Ptr)
+}
+
+// --- [ invoke ] --------------------------------------------------------------
+
+// emitInvokeCallee returns the callee and receiver of the given invoke-mode Go
+// SSA call, looking up the method in the interface method table of the
+// interface value, emitting to fn.
+func (fn *Func) emitInvokeCallee(goCall *ssa.CallCommon) (callee, recv irvalue.Value) {
+	iface := fn.useValue(goCall.Value)
+	goIfaceType := goCall.Value.Type().Underlying().(*gotypes.Interface)
+	index := methodIndex(goIfaceType, goCall.Method)
+	tab := fn.cur.NewExtractValue(iface, 0)
+	addMetadata(tab, "field", "tab")
+	data := fn.cur.NewExtractValue(iface, 1)
+	addMetadata(data, "field", "data")
+	// Load function pointer from the interface method table.
+	itabType := fn.m.irTypeFromName("runtime.itab")
+	indices := []irvalue.Value{
+		irconstant.NewInt(irtypes.I64, 0),
+		irconstant.NewInt(irtypes.I32, 2), // fun
+		irconstant.NewInt(irtypes.I64, int64(index)),
+	}
+	fnPtrAddr := fn.cur.NewGetElementPtr(itabType, tab, indices...)
+	fnPtr := fn.cur.NewLoad(irtypes.I8Ptr, fnPtrAddr)
+	addMetadata(fnPtr, "method", goCall.Method.Name())
+	// The receiver of methods in interface method tables is passed as a generic
+	// pointer.
+	sig := fn.m.irFuncTypeFromGoSig(goCall.Signature(), irtypes.I8Ptr)
+	callee = fn.cur.NewBitCast(fnPtr, irtypes.NewPointer(sig))
+	return callee, data
+}
+
+// --- [ type assertion ] ------------------------------------------------------
+
+// emitAssertConcrete compiles a type assertion of the interface value with the
+// given interface method table and data pointer (of static Go interface type
+// goIfaceType) to the concrete Go type goType, emitting to fn. The returned ok
+// value reports whether the type assertion succeeded. If commaOk is false, a
+// failed type assertion panics at run time; otherwise, v is the zero value of
+// goType on failure.
+func (fn *Func) emitAssertConcrete(tab, data irvalue.Value, goIfaceType, goType gotypes.Type, commaOk bool) (v, ok irvalue.Value) {
+	want := fn.m.getTypeDesc(goType)
+	iface := fn.m.getTypeDesc(goIfaceType)
+	typeassert := fn.m.getPredeclaredFunc("runtime.typeassert")
+	ok = fn.cur.NewCall(typeassert, tab, want, iface, irconstant.NewBool(commaOk))
+	typ := fn.m.irTypeFromGo(goType)
+	zero := irconstant.NewZeroInitializer(typ)
+	if isDirectIface(goType) {
+		// Pointer shaped value stored directly in the data pointer.
+		v = fn.cur.NewBitCast(data, typ)
+		if commaOk {
+			v = fn.cur.NewSelect(ok, v, zero)
+		}
+		return v, ok
+	}
+	// Load boxed value from the data pointer.
+	ptr := fn.cur.NewBitCast(data, irtypes.NewPointer(typ))
+	if !commaOk {
+		return fn.cur.NewLoad(typ, ptr), ok
+	}
+	// The data pointer may only be dereferenced on success, as the interface
+	// value may hold a different type (or be nil).
+	pred := fn.cur
+	okBlock := fn.newAuxBlock("typeassert.ok")
+	doneBlock := fn.newAuxBlock("typeassert.done")
+	fn.cur.NewCondBr(ok, okBlock, doneBlock)
+	fn.cur = okBlock
+	value := fn.cur.NewLoad(typ, ptr)
+	fn.cur.NewBr(doneBlock)
+	fn.cur = doneBlock
+	v = fn.cur.NewPhi(ir.NewIncoming(value, okBlock), ir.NewIncoming(zero, pred))
+	return v, ok
+}
+
+// emitAssertIface compiles a type assertion of the interface value with the
+// given interface method table and data pointer to the Go interface type
+// goIfaceType, emitting to fn. The interface method table of the resulting
+// interface value is located at run time. The returned ok value reports whether
+// the type assertion succeeded. If commaOk is false, a failed type assertion
+// panics at run time; otherwise, v is a nil interface value on failure.
+func (fn *Func) emitAssertIface(tab, data irvalue.Value, goIfaceType gotypes.Type, commaOk bool) (v, ok irvalue.Value) {
+	inter := fn.m.getTypeDesc(goIfaceType)
+	getitab := fn.m.getPredeclaredFunc("runtime.getitab")
+	newTab := fn.cur.NewCall(getitab, inter, tab, irconstant.NewBool(commaOk))
+	ok = fn.cur.NewICmp(irenum.IPredNE, newTab, irconstant.NewNull(newTab.Type().(*irtypes.PointerType)))
+	if commaOk {
+		data = fn.cur.NewSelect(ok, data, irconstant.NewNull(irtypes.I8Ptr))
+	}
+	typ := fn.m.irTypeFromGo(goIfaceType)
+	insertTab := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(typ), newTab, 0)
+	addMetadata(insertTab, "field", "tab")
+	insertData := fn.cur.NewInsertValue(insertTab, data, 1)
+	addMetadata(insertData, "field", "data")
+	return insertData, ok
+}
+
+// --- [ equal ] ---------------------------------------------------------------
+
+// emitInterfaceEqual compiles an equality comparison of the given interface
+// values to corresponding LLVM IR instructions, emitting to fn.
+//
+// Two interface values are equal if they are both nil, or if they hold the same
+// dynamic type and equal dynamic values.
+func (fn *Func) emitInterfaceEqual(x, y irvalue.Value) irValueInstruction {
+	xTab := fn.cur.NewExtractValue(x, 0)
+	addMetadata(xTab, "field", "tab")
+	xData := fn.cur.NewExtractValue(x, 1)
+	addMetadata(xData, "field", "data")
+	yTab := fn.cur.NewExtractValue(y, 0)
+	addMetadata(yTab, "field", "tab")
+	yData := fn.cur.NewExtractValue(y, 1)
+	addMetadata(yData, "field", "data")
+	ifaceeq := fn.m.getPredeclaredFunc("runtime.ifaceeq")
+	return fn.cur.NewCall(ifaceeq, xTab, xData, yTab, yData)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// isDirectIface reports whether values of the given Go type are stored
+// directly in the data pointer of interface values.
+func isDirectIface(goType gotypes.Type) bool {
+	switch t := goType.Underlying().(type) {
+	case *gotypes.Pointer, *gotypes.Map:
+		return true
+	case *gotypes.Basic:
+		return t.Kind() == gotypes.UnsafePointer
+	default:
+		return false
+	}
+}
+
+// Kinds of runtime type descriptors; using the same numbering as reflect.Kind.
+const (
+	kindArray         = 17
+	kindChan          = 18
+	kindFunc          = 19
+	kindInterface     = 20
+	kindMap           = 21
+	kindPtr           = 22
+	kindSlice         = 23
+	kindString        = 24
+	kindStruct        = 25
+	kindUnsafePointer = 26
+	// kindDirectIface is set in the kind of types stored directly in the data
+	// pointer of interface values.
+	kindDirectIface = 32
+)
+
+// kindOf returns the kind of the runtime type descriptor of the given Go type.
+func kindOf(goType gotypes.Type) uint8 {
+	var kind uint8
+	switch t := goType.Underlying().(type) {
+	case *gotypes.Basic:
+		switch t.Kind() {
+		case gotypes.String, gotypes.UntypedString:
+			kind = kindString
+		case gotypes.UnsafePointer:
+			kind = kindUnsafePointer
+		default:
+			// Boolean, integer, floating-point and complex kinds of go/types
+			// coincide with those of reflect.
+			kind = uint8(t.Kind())
+		}
+	case *gotypes.Array:
+		kind = kindArray
+	case *gotypes.Chan:
+		kind = kindChan
+	case *gotypes.Signature:
+		kind = kindFunc
+	case *gotypes.Interface:
+		kind = kindInterface
+	case *gotypes.Map:
+		kind = kindMap
+	case *gotypes.Pointer:
+		kind = kindPtr
+	case *gotypes.Slice:
+		kind = kindSlice
+	case *gotypes.Struct:
+		kind = kindStruct
+	default:
+		panic(fmt.Errorf("support for type %T as runtime type descriptor not yet implemented", t))
+	}
+	if isDirectIface(goType) {
+		kind |= kindDirectIface
+	}
+	return kind
+}
+
+// recvTypeOf returns the receiver type of methods invoked through interface
+// values holding the given concrete Go type; which is the type of the data
+// pointer of such interface values.
+func recvTypeOf(goType gotypes.Type) gotypes.Type {
+	if isDirectIface(goType) {
+		return goType
+	}
+	return gotypes.NewPointer(goType)
+}
+
+// methodKey returns the key used to match the methods of concrete types against
+// the methods of interface types at run time; which is the method name (package
+// qualified if unexported) followed by the method signature.
+func (m *Module) methodKey(goMethod *gotypes.Func) string {
+	sig := goMethod.Type().(*gotypes.Signature)
+	goSig := gotypes.NewSignature(nil, sig.Params(), sig.Results(), sig.Variadic())
+	return fmt.Sprintf("%s %s", goMethod.Id(), m.typeName(goSig, nil))
+}
+
+// methodIndex returns the index of the given method in the method list of the
+// specified Go interface type.
+func methodIndex(goIfaceType *gotypes.Interface, goMethod *gotypes.Func) int {
+	for i := 0; i < goIfaceType.NumMethods(); i++ {
+		if goIfaceType.Method(i).Id() == goMethod.Id() {
+			return i
+		}
+	}
+	panic(fmt.Errorf("unable to locate method %q in interface type %v", goMethod.Name(), goIfaceType))
+}
+
+// typeString returns the string representation of the given Go type, as used
+// by the Go runtime (e.g. "main.T" and "interface {}").
+func typeString(goType gotypes.Type) string {
+	s := gotypes.TypeString(goType, func(pkg *gotypes.Package) string {
+		return pkg.Name()
+	})
+	return strings.Replace(s, "interface{", "interface {", -1)
+}
+
+// sizeof returns the size in bytes of the given LLVM IR type, as an LLVM IR
+// constant expression.
+func (m *Module) sizeof(typ irtypes.Type) irconstant.Constant {
+	// ptrtoint (T* getelementptr (T, T* null, i32 1) to int)
+	one := irconstant.NewInt(irtypes.I32, 1)
+	end := irconstant.NewGetElementPtr(typ, irconstant.NewNull(irtypes.NewPointer(typ)), one)
+	return irconstant.NewPtrToInt(end, m.irTypeFromName("int"))
+}
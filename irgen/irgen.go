@@ -41,6 +41,17 @@ func SetWarningOutput(w io.Writer) {
 	warn.SetOutput(w)
 }
 
+// boundsCheck specifies whether to emit bounds checks of index and slice
+// operations.
+var boundsCheck = true
+
+// SetBoundsCheck specifies whether to emit bounds checks of index and slice
+// operations (enabled by default). Disabling bounds checks is unsafe, as out of
+// range accesses are left undetected.
+func SetBoundsCheck(enabled bool) {
+	boundsCheck = enabled
+}
+
 // CompilePackage compiles the given Go SSA package into an LLVM IR module.
 func CompilePackage(goPkg *ssa.Package) (*ir.Module, error) {
 	dbg.Println("CompilePackage")
@@ -57,8 +68,14 @@ func CompilePackage(goPkg *ssa.Package) (*ir.Module, error) {
 	// functions.
 	m.initPredeclaredFuncs()
 
-	// Compile type definitions of Go SSA package and its dependencies.
+	// Index type definitions of Go SSA package and its dependencies.
 	done := make(map[*ssa.Package]bool)
+	if err := m.indexAllPkgTypeDefs(goPkg, done); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// Compile type definitions of Go SSA package and its dependencies.
+	done = make(map[*ssa.Package]bool)
 	if err := m.emitAllPkgTypeDefs(goPkg, done); err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -96,6 +113,11 @@ func CompilePackage(goPkg *ssa.Package) (*ir.Module, error) {
 		}
 	}
 
+	// Compile synthetic functions (e.g. method wrappers) used by Go SSA package.
+	if err := m.emitSynthFuncs(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
 	// Hook up forward declaration (function stubs).
 	//
 	// ref: https://dave.cheney.net/2019/08/20/go-compiler-intrinsics
@@ -107,6 +129,11 @@ func CompilePackage(goPkg *ssa.Package) (*ir.Module, error) {
 			continue
 		}
 		f := m.getFunc(goFunc)
+		if goFunc.Pkg == nil && len(goFunc.Synthetic) > 0 {
+			// skip synthetic functions (e.g. $bound and $thunk wrappers), which
+			// have no package.
+			continue
+		}
 		if goFunc.Pkg != m.goPkg {
 			// skip external declarations.
 			externalFuncs = append(externalFuncs, f)
@@ -164,6 +191,51 @@ func CompilePackage(goPkg *ssa.Package) (*ir.Module, error) {
 
 // --- [ index ] ---------------------------------------------------------------
 
+// ~~~ [ types ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// indexAllPkgTypeDefs indexes the type definitions of the given Go SSA package
+// and its dependencies, creating corresponding LLVM IR type definitions,
+// emitting to m.
+func (m *Module) indexAllPkgTypeDefs(goPkg *ssa.Package, done map[*ssa.Package]bool) error {
+	if done[goPkg] {
+		return nil
+	}
+	done[goPkg] = true
+	for _, imp := range goPkg.Pkg.Imports() {
+		goImpPkg := goPkg.Prog.Package(imp)
+		if err := m.indexAllPkgTypeDefs(goImpPkg, done); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if err := m.indexPkgTypeDefs(goPkg); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// indexPkgTypeDefs indexes the type definitions of the given Go SSA package,
+// creating corresponding LLVM IR type definitions, emitting to m.
+func (m *Module) indexPkgTypeDefs(goPkg *ssa.Package) error {
+	// Sort type names of Go SSA package.
+	dbg.Println("indexing types of package:", goPkg.Pkg.Path())
+	var goTypes []*ssa.Type
+	for _, goMember := range goPkg.Members {
+		if goType, ok := goMember.(*ssa.Type); ok {
+			goTypes = append(goTypes, goType)
+		}
+	}
+	sort.Slice(goTypes, func(i, j int) bool {
+		return goTypes[i].RelString(nil) < goTypes[j].RelString(nil)
+	})
+	// Index type definitions of Go SSA package.
+	for _, goType := range goTypes {
+		if err := m.indexType(goType); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
 // ~~~ [ members ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // indexAllPkgMembers indexes the members of the given Go SSA package and its
@@ -224,9 +296,8 @@ func (m *Module) indexMember(goMember ssa.Member, external bool) error {
 	case *ssa.Function:
 		return m.indexFunc(goMember)
 	case *ssa.Type:
-		// TODO: figure out how to index type definitions.
-		//return m.indexType(goMember)
-		return nil // ignore indexing *ssa.Type for now
+		// Type definitions are indexed by indexAllPkgTypeDefs.
+		return nil
 	default:
 		panic(fmt.Errorf("support for SSA member %T (%q) not yet implemented", goMember, goMember.Name()))
 	}
@@ -394,6 +465,24 @@ func (m *Module) emitTypeMethod(goNamedType *gotypes.Named) error {
 	return nil
 }
 
+// ~~~ [ synthetic functions ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// emitSynthFuncs compiles the synthetic functions (e.g. method wrappers) which
+// have been indexed on demand into LLVM IR, emitting to m.
+//
+// Pre-condition: index methods and globals of m.
+func (m *Module) emitSynthFuncs() error {
+	// Compiling a synthetic function may index further synthetic functions.
+	for len(m.synthFuncs) > 0 {
+		goFunc := m.synthFuncs[0]
+		m.synthFuncs = m.synthFuncs[1:]
+		if err := m.emitFunc(goFunc); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
 // --- [ compile ] -------------------------------------------------------------
 
 // emitMember compiles the given Go SSA member into LLVM IR, emitting to m.
@@ -0,0 +1,101 @@
+package irgen
+
+import (
+	gotypes "go/types"
+
+	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
+	irtypes "github.com/llir/llvm/ir/types"
+	irvalue "github.com/llir/llvm/ir/value"
+)
+
+// --- [ map access ] ----------------------------------------------------------
+
+// emitMapAccess emits a lookup of the element with the given key in the map h
+// of the given Go map type, emitting to fn. The zero value of the element type
+// is returned if the key is not present; and ok reports whether the key was
+// present.
+func (fn *Func) emitMapAccess(h, key irvalue.Value, goMapType *gotypes.Map) (v, ok irvalue.Value) {
+	elemType := fn.m.irTypeFromGo(goMapType.Elem())
+	elemPtrType := irtypes.NewPointer(elemType)
+	mapaccess := fn.m.getPredeclaredFunc("runtime.mapaccess")
+	p := fn.cur.NewCall(mapaccess, h, fn.emitSpill(key))
+	ok = fn.cur.NewICmp(irenum.IPredNE, p, irconstant.NewNull(irtypes.I8Ptr))
+	// Load the zero value of the element type from a zero-initialized slot if
+	// the key is not present.
+	zero := fn.entry.NewAlloca(elemType)
+	fn.entry.NewStore(irconstant.NewZeroInitializer(elemType), zero)
+	elemPtr := fn.cur.NewBitCast(p, elemPtrType)
+	ptr := fn.cur.NewSelect(ok, elemPtr, zero)
+	v = fn.cur.NewLoad(elemType, ptr)
+	return v, ok
+}
+
+// emitMapAssign emits an assignment of the value v to the element with the
+// given key in the map h, emitting to fn.
+func (fn *Func) emitMapAssign(h, key, v irvalue.Value) {
+	mapassign := fn.m.getPredeclaredFunc("runtime.mapassign")
+	p := fn.cur.NewCall(mapassign, h, fn.emitSpill(key))
+	elemPtr := fn.cur.NewBitCast(p, irtypes.NewPointer(v.Type()))
+	fn.cur.NewStore(v, elemPtr)
+}
+
+// emitMapDelete emits a removal of the element with the given key from the map
+// h, emitting to fn.
+func (fn *Func) emitMapDelete(h, key irvalue.Value) irValueInstruction {
+	mapdelete := fn.m.getPredeclaredFunc("runtime.mapdelete")
+	return fn.cur.NewCall(mapdelete, h, fn.emitSpill(key))
+}
+
+// --- [ map iteration ] -------------------------------------------------------
+
+// emitMapIterNext emits an advance of the map iterator iter, emitting to fn.
+// The boolean result ok reports whether an element was produced, in which case
+// k and v hold its key and value. The key (or value) is only loaded if the
+// given Go key (or element) type is valid; otherwise, k (or v) is nil.
+func (fn *Func) emitMapIterNext(iter irvalue.Value, goKeyType, goElemType gotypes.Type) (ok, k, v irvalue.Value) {
+	var keySlot, elemSlot irvalue.Value
+	keyPtr := irvalue.Value(irconstant.NewNull(irtypes.I8Ptr))
+	elemPtr := irvalue.Value(irconstant.NewNull(irtypes.I8Ptr))
+	if isValidType(goKeyType) {
+		keySlot = fn.entry.NewAlloca(fn.m.irTypeFromGo(goKeyType))
+		keyPtr = fn.cur.NewBitCast(keySlot, irtypes.I8Ptr)
+	}
+	if isValidType(goElemType) {
+		elemSlot = fn.entry.NewAlloca(fn.m.irTypeFromGo(goElemType))
+		elemPtr = fn.cur.NewBitCast(elemSlot, irtypes.I8Ptr)
+	}
+	mapiternext := fn.m.getPredeclaredFunc("runtime.mapiternext")
+	ok = fn.cur.NewCall(mapiternext, iter, keyPtr, elemPtr)
+	if keySlot != nil {
+		k = fn.cur.NewLoad(fn.m.irTypeFromGo(goKeyType), keySlot)
+	}
+	if elemSlot != nil {
+		v = fn.cur.NewLoad(fn.m.irTypeFromGo(goElemType), elemSlot)
+	}
+	return ok, k, v
+}
+
+// ### [ Helper functions ] ####################################################
+
+// emitSpill stores the given value to a stack slot, allocated in the entry
+// basic block of fn, and returns the address of the stack slot as an unsafe
+// pointer. The runtime map functions receive keys by address.
+func (fn *Func) emitSpill(x irvalue.Value) irvalue.Value {
+	slot := fn.entry.NewAlloca(x.Type())
+	fn.cur.NewStore(x, slot)
+	return fn.cur.NewBitCast(slot, irtypes.I8Ptr)
+}
+
+// isMapType reports whether the given Go type is a map type.
+func isMapType(goType gotypes.Type) bool {
+	_, ok := goType.Underlying().(*gotypes.Map)
+	return ok
+}
+
+// isValidType reports whether the given Go type is valid; as opposed to the
+// invalid type used by Go SSA for unused components of tuples (e.g. the key of
+// next instructions in range statements without key variable).
+func isValidType(goType gotypes.Type) bool {
+	return goType != gotypes.Typ[gotypes.Invalid]
+}
@@ -1,6 +1,7 @@
 package irgen
 
 import (
+	gotypes "go/types"
 	"sync"
 
 	"github.com/llir/llvm/ir"
@@ -28,6 +29,27 @@ type Module struct {
 	globals map[ssa.Value]irvalue.Value
 	// Map from predeclared Go function name to LLVM IR function.
 	predeclaredFuncs map[string]*ir.Func
+	// Map from Go type name to LLVM IR global variable holding the runtime type
+	// descriptor of the given Go type.
+	typeDescs map[string]*ir.Global
+	// Map from (Go concrete type name, Go interface type name) pair to LLVM IR
+	// global variable holding the interface method table of the given pair.
+	itabs map[string]*ir.Global
+	// Synthetic Go SSA functions (e.g. method wrappers) indexed on demand and
+	// pending compilation.
+	synthFuncs []*ssa.Function
+	// Map from function-local Go type name to unique type name (e.g. "pair#1").
+	localTypeNames map[*gotypes.TypeName]string
+	// Full type names of the named Go types whose type definitions are being
+	// compiled; used to break cycles of recursive types not passing through
+	// structure types.
+	pendingTypes map[string]bool
+	// Top of the defer stack of the runtime (external global variable); nil if
+	// not yet declared.
+	deferStack *ir.Global
+	// Panic recoverable by the deferred function being called by the runtime
+	// (external global variable); nil if not yet declared.
+	recoverPanic *ir.Global
 
 	// Mutex to ensure that access to strings and curStrNum is thread-safe.
 	stringsMutex sync.Mutex
@@ -49,6 +71,10 @@ func NewModule(goPkg *ssa.Package) *Module {
 		consts:           make(map[*ssa.NamedConst]irconstant.Constant),
 		globals:          make(map[ssa.Value]irvalue.Value),
 		predeclaredFuncs: make(map[string]*ir.Func),
+		typeDescs:        make(map[string]*ir.Global),
+		itabs:            make(map[string]*ir.Global),
+		localTypeNames:   make(map[*gotypes.TypeName]string),
+		pendingTypes:     make(map[string]bool),
 		strings:          make(map[string]*ir.Global),
 	}
 }
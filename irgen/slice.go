@@ -0,0 +1,93 @@
+package irgen
+
+import (
+	gotypes "go/types"
+
+	irconstant "github.com/llir/llvm/ir/constant"
+	irtypes "github.com/llir/llvm/ir/types"
+	irvalue "github.com/llir/llvm/ir/value"
+	"golang.org/x/tools/go/ssa"
+)
+
+// --- [ append ] --------------------------------------------------------------
+
+// emitAppend compiles the given call to the builtin `append` function with the
+// specified arguments, emitting to fn.
+//
+// The second argument holds the appended elements, either as a slice (packed by
+// Go SSA for variadic calls; nil if none) or as a string (`append(b, s...)`).
+func (fn *Func) emitAppend(goInst *ssa.Call, args []irvalue.Value) error {
+	dbg.Println("emitAppend")
+	typ := fn.m.irTypeFromGo(goInst.Type()).(*irtypes.StructType)
+	dataType := typ.Fields[0].(*irtypes.PointerType)
+	s := fn.emitRuntimeSlice(args[0])
+	p, n := fn.emitDataLen(args[1])
+	appendslice := fn.m.getPredeclaredFunc("runtime.appendslice")
+	result := fn.cur.NewCall(appendslice, fn.m.sizeof(dataType.ElemType), s, p, n)
+	inst := fn.emitFromRuntimeSlice(result, typ)
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// --- [ copy ] ----------------------------------------------------------------
+
+// emitCopy compiles the given call to the builtin `copy` function with the
+// specified arguments, emitting to fn. The source is either a slice or a
+// string (`copy(b, s)`).
+func (fn *Func) emitCopy(goInst *ssa.Call, args []irvalue.Value) error {
+	dbg.Println("emitCopy")
+	dstType := args[0].Type().(*irtypes.StructType)
+	dataType := dstType.Fields[0].(*irtypes.PointerType)
+	dst, dstLen := fn.emitDataLen(args[0])
+	src, srcLen := fn.emitDataLen(args[1])
+	slicecopy := fn.m.getPredeclaredFunc("runtime.slicecopy")
+	inst := fn.cur.NewCall(slicecopy, dst, dstLen, src, srcLen, fn.m.sizeof(dataType.ElemType))
+	inst.SetName(goInst.Name())
+	fn.setLocal(goInst, inst)
+	dbg.Println("   inst:", inst.LLString())
+	return nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// emitDataLen returns the data pointer (as unsafe pointer) and length of the
+// given slice or string value, emitting to fn.
+func (fn *Func) emitDataLen(x irvalue.Value) (data, length irvalue.Value) {
+	dataField := fn.cur.NewExtractValue(x, 0)
+	addMetadata(dataField, "field", "data")
+	lengthField := fn.cur.NewExtractValue(x, 1)
+	addMetadata(lengthField, "field", "len")
+	return fn.cur.NewBitCast(dataField, irtypes.I8Ptr), lengthField
+}
+
+// emitRuntimeSlice converts the given slice value to the untyped slice
+// representation of the runtime (%runtime.slice), emitting to fn.
+func (fn *Func) emitRuntimeSlice(x irvalue.Value) irvalue.Value {
+	sliceType := fn.m.irTypeFromName("runtime.slice")
+	data, length := fn.emitDataLen(x)
+	capacity := fn.cur.NewExtractValue(x, 2)
+	addMetadata(capacity, "field", "cap")
+	s := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(sliceType), data, 0)
+	s = fn.cur.NewInsertValue(s, length, 1)
+	return fn.cur.NewInsertValue(s, capacity, 2)
+}
+
+// emitFromRuntimeSlice converts the given slice value in the untyped slice
+// representation of the runtime (%runtime.slice) to the specified slice type,
+// emitting to fn.
+func (fn *Func) emitFromRuntimeSlice(x irvalue.Value, typ *irtypes.StructType) irValueInstruction {
+	dataField := fn.cur.NewExtractValue(x, 0)
+	addMetadata(dataField, "field", "data")
+	data := fn.cur.NewBitCast(dataField, typ.Fields[0])
+	s := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(typ), data, 0)
+	s = fn.cur.NewInsertValue(s, fn.cur.NewExtractValue(x, 1), 1)
+	return fn.cur.NewInsertValue(s, fn.cur.NewExtractValue(x, 2), 2)
+}
+
+// isSliceType reports whether the given Go type is a slice type.
+func isSliceType(goType gotypes.Type) bool {
+	_, ok := goType.Underlying().(*gotypes.Slice)
+	return ok
+}
@@ -2,9 +2,15 @@ package irgen
 
 import (
 	"fmt"
+	"go/token"
+	gotypes "go/types"
 
 	"github.com/llir/llvm/ir"
 	irconstant "github.com/llir/llvm/ir/constant"
+	irenum "github.com/llir/llvm/ir/enum"
+	irtypes "github.com/llir/llvm/ir/types"
+	irvalue "github.com/llir/llvm/ir/value"
+	"golang.org/x/tools/go/ssa"
 )
 
 // emitStringLit compiles the given Go string literal into LLVM IR, emitting to
@@ -38,3 +44,191 @@ func (m *Module) nextStrName() string {
 	m.curStrNum++
 	return strName
 }
+
+// --- [ string iteration ] ----------------------------------------------------
+
+// emitStringIterInit emits a new iterator over the runes of the string s,
+// emitting to fn. The iterator is a pointer to a stack slot holding the string
+// and the byte offset of the next rune.
+//
+//	type stringIter struct {
+//		s string
+//		k int
+//	}
+func (fn *Func) emitStringIterInit(s irvalue.Value) irvalue.Value {
+	iterType := fn.stringIterType()
+	iter := fn.entry.NewAlloca(iterType)
+	s = fn.cast(s, iterType.Fields[0])
+	init := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(iterType), s, 0)
+	fn.cur.NewStore(init, iter)
+	return iter
+}
+
+// emitStringIterNext emits an advance of the string iterator iter, emitting to
+// fn. The boolean result ok reports whether a rune was produced, in which case
+// k holds its byte offset and v the decoded rune.
+func (fn *Func) emitStringIterNext(iter irvalue.Value) (ok, k, v irvalue.Value) {
+	iterType := fn.stringIterType()
+	stringType := iterType.Fields[0]
+	intType := iterType.Fields[1].(*irtypes.IntType)
+	zero := irconstant.NewInt(irtypes.I32, 0)
+	one := irconstant.NewInt(irtypes.I32, 1)
+	sPtr := fn.cur.NewGetElementPtr(iterType, iter, zero, zero)
+	s := fn.cur.NewLoad(stringType, sPtr)
+	kPtr := fn.cur.NewGetElementPtr(iterType, iter, zero, one)
+	k = fn.cur.NewLoad(intType, kPtr)
+	length := fn.cur.NewExtractValue(s, 1)
+	addMetadata(length, "field", "len")
+	ok = fn.cur.NewICmp(irenum.IPredSLT, k, length)
+	// Only decode the rune at offset k if within bounds.
+	pred := fn.cur
+	decodeBlock := fn.newAuxBlock("rangestring.decode")
+	doneBlock := fn.newAuxBlock("rangestring.done")
+	fn.cur.NewCondBr(ok, decodeBlock, doneBlock)
+	fn.cur = decodeBlock
+	decoderune := fn.m.getPredeclaredFunc("runtime.decoderune")
+	result := fn.cur.NewCall(decoderune, s, k)
+	r := fn.cur.NewExtractValue(result, 0)
+	pos := fn.cur.NewExtractValue(result, 1)
+	fn.cur.NewStore(pos, kPtr)
+	fn.cur.NewBr(doneBlock)
+	fn.cur = doneBlock
+	runeZero := irconstant.NewInt(r.Type().(*irtypes.IntType), 0)
+	v = fn.cur.NewPhi(ir.NewIncoming(r, decodeBlock), ir.NewIncoming(runeZero, pred))
+	return ok, k, v
+}
+
+// stringIterType returns the LLVM IR type of string iterators.
+func (fn *Func) stringIterType() *irtypes.StructType {
+	return irtypes.NewStruct(fn.m.irTypeFromName("string"), fn.m.irTypeFromName("int"))
+}
+
+// --- [ string conversion ] ---------------------------------------------------
+
+// emitStringConvert emits a conversion of the given value from Go type goFrom
+// to Go type goTo, where either is a string type, emitting to fn. The contents
+// of strings and slices are copied, as strings are immutable.
+func (fn *Func) emitStringConvert(from irvalue.Value, goFrom, goTo gotypes.Type) irValueInstruction {
+	var funcName string
+	switch {
+	// string -> []byte
+	case isStringType(goFrom) && isSliceOfKind(goTo, gotypes.Byte):
+		funcName = "runtime.stringtoslicebyte"
+	// string -> []rune
+	case isStringType(goFrom) && isSliceOfKind(goTo, gotypes.Rune):
+		funcName = "runtime.stringtoslicerune"
+	// []byte -> string
+	case isSliceOfKind(goFrom, gotypes.Byte) && isStringType(goTo):
+		funcName = "runtime.slicebytetostring"
+	// []rune -> string
+	case isSliceOfKind(goFrom, gotypes.Rune) && isStringType(goTo):
+		funcName = "runtime.slicerunetostring"
+	// integer -> string
+	case isIntegerType(goFrom) && isStringType(goTo):
+		funcName = "runtime.intstring"
+		from = fn.convert(from, goFrom, gotypes.Typ[gotypes.Int64])
+	default:
+		panic(fmt.Errorf("support for converting from type %v to type %v not yet implemented", goFrom, goTo))
+	}
+	f := fn.m.getPredeclaredFunc(funcName)
+	from = fn.cast(from, f.Params[0].Typ)
+	result := fn.cur.NewCall(f, from)
+	// Both the call and the cast of its result to a defined type are
+	// instructions.
+	return fn.cast(result, fn.m.irTypeFromGo(goTo)).(irValueInstruction)
+}
+
+// --- [ string concatenation ] ------------------------------------------------
+
+// emitStringConcat emits the concatenation of the operands of the given string
+// concatenation, emitting to fn. Nested concatenations (e.g. `a + b + c`) are
+// flattened, so that the result is allocated once.
+func (fn *Func) emitStringConcat(goInst *ssa.BinOp) irValueInstruction {
+	stringType := fn.m.irTypeFromName("string")
+	var strs []irvalue.Value
+	for _, goOperand := range stringConcatOperands(goInst) {
+		strs = append(strs, fn.cast(fn.useValue(goOperand), stringType))
+	}
+	// Pass operands as slice backed by a stack allocated array.
+	arrayType := irtypes.NewArray(uint64(len(strs)), stringType)
+	array := fn.entry.NewAlloca(arrayType)
+	zero := irconstant.NewInt(irtypes.I64, 0)
+	for i, str := range strs {
+		elem := fn.cur.NewGetElementPtr(arrayType, array, zero, irconstant.NewInt(irtypes.I64, int64(i)))
+		fn.cur.NewStore(str, elem)
+	}
+	sliceType := fn.m.newSliceType(stringType)
+	intType := fn.m.irTypeFromName("int").(*irtypes.IntType)
+	n := irconstant.NewInt(intType, int64(len(strs)))
+	dataPtr := fn.cur.NewGetElementPtr(arrayType, array, zero, zero)
+	slice := fn.cur.NewInsertValue(irconstant.NewZeroInitializer(sliceType), dataPtr, 0)
+	slice = fn.cur.NewInsertValue(slice, n, 1)
+	slice = fn.cur.NewInsertValue(slice, n, 2)
+	concatstrings := fn.m.getPredeclaredFunc("runtime.concatstrings")
+	result := fn.cur.NewCall(concatstrings, slice)
+	// Both the call and the cast of its result to a defined type are
+	// instructions.
+	return fn.cast(result, fn.m.irTypeFromGo(goInst.Type())).(irValueInstruction)
+}
+
+// stringConcatOperands returns the operands of the given string concatenation,
+// flattening nested concatenations which are only used as operands of string
+// concatenations.
+func stringConcatOperands(goInst *ssa.BinOp) []ssa.Value {
+	var goOperands []ssa.Value
+	for _, goOperand := range []ssa.Value{goInst.X, goInst.Y} {
+		if goBinOp, ok := goOperand.(*ssa.BinOp); ok && isStringConcat(goBinOp) && isStringConcatOperand(goBinOp) {
+			goOperands = append(goOperands, stringConcatOperands(goBinOp)...)
+			continue
+		}
+		goOperands = append(goOperands, goOperand)
+	}
+	return goOperands
+}
+
+// ### [ Helper functions ] ####################################################
+
+// isStringType reports whether the given Go type is a string type.
+func isStringType(goType gotypes.Type) bool {
+	t, ok := goType.Underlying().(*gotypes.Basic)
+	return ok && t.Info()&gotypes.IsString != 0
+}
+
+// isIntegerType reports whether the given Go type is an integer type.
+func isIntegerType(goType gotypes.Type) bool {
+	t, ok := goType.Underlying().(*gotypes.Basic)
+	return ok && t.Info()&gotypes.IsInteger != 0
+}
+
+// isSliceOfKind reports whether the given Go type is a slice type with element
+// type of the specified basic kind (e.g. []byte).
+func isSliceOfKind(goType gotypes.Type, kind gotypes.BasicKind) bool {
+	t, ok := goType.Underlying().(*gotypes.Slice)
+	if !ok {
+		return false
+	}
+	elem, ok := t.Elem().Underlying().(*gotypes.Basic)
+	return ok && elem.Kind() == kind
+}
+
+// isStringConcat reports whether the given Go SSA binary operation instruction
+// is a string concatenation.
+func isStringConcat(goInst *ssa.BinOp) bool {
+	return goInst.Op == token.ADD && isStringType(goInst.Type())
+}
+
+// isStringConcatOperand reports whether the result of the given string
+// concatenation is only used as operand of other string concatenations.
+func isStringConcatOperand(goInst *ssa.BinOp) bool {
+	refs := *goInst.Referrers()
+	if len(refs) == 0 {
+		return false
+	}
+	for _, ref := range refs {
+		goBinOp, ok := ref.(*ssa.BinOp)
+		if !ok || !isStringConcat(goBinOp) {
+			return false
+		}
+	}
+	return true
+}
@@ -108,17 +108,172 @@ func (m *Module) initPredeclaredTypes() {
 	unsafePointerType.SetName("unsafe.Pointer")
 	m.types[unsafePointerType.Name()] = unsafePointerType
 	m.Module.TypeDefs = append(m.Module.TypeDefs, unsafePointerType)
-	// error interface type.
-	// TODO: figure out how to define interface types.
+	// runtime method type.
 	// TODO: add support for LLVM IR structure types with field names.
-	//errorType = NewStruct(
-	//   Field{Name: "type", Type: stringType},
-	//   Field{Name: "value", Type: irtypes.I8Ptr}, // generic pointer type
+	//methodType = NewStruct(
+	//   Field{Name: "name", Type: stringType},
+	//   Field{Name: "fn", Type: irtypes.I8Ptr}, // generic function pointer
 	//)
-	errorType := irtypes.NewStruct(
+	methodType := irtypes.NewStruct(
 		stringType,
-		irtypes.I8Ptr, // generic pointer type
+		irtypes.I8Ptr, // generic function pointer
+	)
+	methodType.SetName("runtime.method")
+	m.types[methodType.Name()] = methodType
+	m.Module.TypeDefs = append(m.Module.TypeDefs, methodType)
+	// runtime type descriptor type.
+	// TODO: add support for LLVM IR structure types with field names.
+	//typeDescType = NewStruct(
+	//   Field{Name: "name", Type: stringType},
+	//   Field{Name: "size", Type: intType},
+	//   Field{Name: "kind", Type: uint8Type},
+	//   Field{Name: "equal", Type: irtypes.NewPointer(equalFuncType)},
+	//   Field{Name: "hash", Type: irtypes.NewPointer(hashFuncType)},
+	//   Field{Name: "methods", Type: irtypes.NewPointer(methodType)},
+	//   Field{Name: "nmethods", Type: intType},
+	//)
+	equalFuncType := irtypes.NewFunc(boolType, irtypes.I8Ptr, irtypes.I8Ptr)
+	hashFuncType := irtypes.NewFunc(uint64Type, irtypes.I8Ptr, uint64Type)
+	typeDescType := irtypes.NewStruct(
+		stringType,
+		intType,
+		uint8Type,
+		irtypes.NewPointer(equalFuncType),
+		irtypes.NewPointer(hashFuncType),
+		irtypes.NewPointer(methodType),
+		intType,
+	)
+	typeDescType.SetName("runtime._type")
+	m.types[typeDescType.Name()] = typeDescType
+	m.Module.TypeDefs = append(m.Module.TypeDefs, typeDescType)
+	// runtime interface method table type.
+	// TODO: add support for LLVM IR structure types with field names.
+	//itabType = NewStruct(
+	//   Field{Name: "inter", Type: irtypes.NewPointer(typeDescType)},
+	//   Field{Name: "type", Type: irtypes.NewPointer(typeDescType)},
+	//   Field{Name: "fun", Type: irtypes.NewArray(0, irtypes.I8Ptr)}, // variable length.
+	//)
+	itabType := irtypes.NewStruct(
+		irtypes.NewPointer(typeDescType),
+		irtypes.NewPointer(typeDescType),
+		irtypes.NewArray(0, irtypes.I8Ptr), // variable length.
+	)
+	itabType.SetName("runtime.itab")
+	m.types[itabType.Name()] = itabType
+	m.Module.TypeDefs = append(m.Module.TypeDefs, itabType)
+	// runtime map entry type.
+	// TODO: add support for LLVM IR structure types with field names.
+	//mapEntryType = NewStruct(
+	//   Field{Name: "next", Type: irtypes.NewPointer(mapEntryType)},
+	//   Field{Name: "hash", Type: uint64Type},
+	//   Field{Name: "deleted", Type: boolType},
+	//)
+	//
+	// The key and element of a map entry are stored after the map entry header.
+	mapEntryType := irtypes.NewStruct()
+	mapEntryType.SetName("runtime.mapentry")
+	mapEntryType.Fields = []irtypes.Type{
+		irtypes.NewPointer(mapEntryType),
+		uint64Type,
+		boolType,
+	}
+	m.types[mapEntryType.Name()] = mapEntryType
+	m.Module.TypeDefs = append(m.Module.TypeDefs, mapEntryType)
+	// runtime map type.
+	// TODO: add support for LLVM IR structure types with field names.
+	//hmapType = NewStruct(
+	//   Field{Name: "count", Type: intType},
+	//   Field{Name: "nbuckets", Type: intType},
+	//   Field{Name: "buckets", Type: irtypes.NewPointer(irtypes.NewPointer(mapEntryType))},
+	//   Field{Name: "key", Type: irtypes.NewPointer(typeDescType)},
+	//   Field{Name: "elem", Type: irtypes.NewPointer(typeDescType)},
+	//   Field{Name: "seed", Type: uint64Type},
+	//)
+	hmapType := irtypes.NewStruct(
+		intType,
+		intType,
+		irtypes.NewPointer(irtypes.NewPointer(mapEntryType)),
+		irtypes.NewPointer(typeDescType),
+		irtypes.NewPointer(typeDescType),
+		uint64Type,
+	)
+	hmapType.SetName("runtime.hmap")
+	m.types[hmapType.Name()] = hmapType
+	m.Module.TypeDefs = append(m.Module.TypeDefs, hmapType)
+	// runtime map iterator type.
+	// TODO: add support for LLVM IR structure types with field names.
+	//hiterType = NewStruct(
+	//   Field{Name: "h", Type: irtypes.NewPointer(hmapType)},
+	//   Field{Name: "entries", Type: irtypes.NewPointer(irtypes.NewPointer(mapEntryType))},
+	//   Field{Name: "n", Type: intType},
+	//   Field{Name: "i", Type: intType},
+	//)
+	hiterType := irtypes.NewStruct(
+		irtypes.NewPointer(hmapType),
+		irtypes.NewPointer(irtypes.NewPointer(mapEntryType)),
+		intType,
+		intType,
 	)
+	hiterType.SetName("runtime.hiter")
+	m.types[hiterType.Name()] = hiterType
+	m.Module.TypeDefs = append(m.Module.TypeDefs, hiterType)
+	// runtime slice type, used to pass slices of any element type to the
+	// runtime.
+	// TODO: add support for LLVM IR structure types with field names.
+	//sliceType = NewStruct(
+	//   Field{Name: "data", Type: irtypes.I8Ptr},
+	//   Field{Name: "len", Type: intType},
+	//   Field{Name: "cap", Type: intType},
+	//)
+	sliceType := irtypes.NewStruct(
+		irtypes.I8Ptr,
+		intType,
+		intType,
+	)
+	sliceType.SetName("runtime.slice")
+	m.types[sliceType.Name()] = sliceType
+	m.Module.TypeDefs = append(m.Module.TypeDefs, sliceType)
+	// runtime defer record type.
+	// TODO: add support for LLVM IR structure types with field names.
+	//deferType = NewStruct(
+	//   Field{Name: "link", Type: irtypes.NewPointer(deferType)},
+	//   Field{Name: "fn", Type: irtypes.NewPointer(deferFuncType)},
+	//   Field{Name: "arg", Type: irtypes.I8Ptr},
+	//   Field{Name: "frame", Type: irtypes.I8Ptr},
+	//)
+	deferFuncType := irtypes.NewFunc(irtypes.Void, irtypes.I8Ptr)
+	deferType := irtypes.NewStruct()
+	deferType.SetName("runtime._defer")
+	deferType.Fields = []irtypes.Type{
+		irtypes.NewPointer(deferType),
+		irtypes.NewPointer(deferFuncType),
+		irtypes.I8Ptr,
+		irtypes.I8Ptr,
+	}
+	m.types[deferType.Name()] = deferType
+	m.Module.TypeDefs = append(m.Module.TypeDefs, deferType)
+	// runtime panic record type.
+	// TODO: add support for LLVM IR structure types with field names.
+	//panicType = NewStruct(
+	//   Field{Name: "link", Type: irtypes.NewPointer(panicType)},
+	//   Field{Name: "tab", Type: irtypes.NewPointer(itabType)},
+	//   Field{Name: "data", Type: irtypes.I8Ptr},
+	//   Field{Name: "recovered", Type: boolType},
+	//   Field{Name: "repanicked", Type: boolType},
+	//)
+	panicType := irtypes.NewStruct()
+	panicType.SetName("runtime._panic")
+	panicType.Fields = []irtypes.Type{
+		irtypes.NewPointer(panicType),
+		irtypes.NewPointer(itabType),
+		irtypes.I8Ptr,
+		boolType,
+		boolType,
+	}
+	m.types[panicType.Name()] = panicType
+	m.Module.TypeDefs = append(m.Module.TypeDefs, panicType)
+	// error interface type.
+	errorType := m.newInterfaceType()
 	errorType.SetName("error")
 	m.types[errorType.Name()] = errorType
 	m.Module.TypeDefs = append(m.Module.TypeDefs, errorType)
@@ -136,9 +291,6 @@ func (m *Module) irTypeFromName(typeName string) irtypes.Type {
 
 // --- [ convert ] -------------------------------------------------------------
 
-// TODO: check if precondition is needed. Type index probably needed for cyclic
-// types.
-
 // irTypeFromGo returns the LLVM IR type corresponding to the given Go type,
 // emitting to m.
 //
@@ -154,10 +306,25 @@ func (m *Module) irTypeFromGo(goType gotypes.Type) irtypes.Type {
 	case *gotypes.Interface:
 		return m.irTypeFromGoInterfaceType(goType)
 	case *gotypes.Map:
-		panic("support for *gotypes.Map not yet implemented")
+		return m.irTypeFromGoMapType(goType)
 	case *gotypes.Named:
 		typeName := m.fullTypeName(goType)
-		return m.irTypeFromName(typeName)
+		if typ, ok := m.types[typeName]; ok {
+			// Note, the type definition may still be opaque if referred to from
+			// within a (mutually) recursive type.
+			return typ
+		}
+		if m.pendingTypes[typeName] {
+			// Referred to from within its own type definition (e.g. `type P *P`
+			// or `type A [2]*A`); as a type definition may only be referred to
+			// from within itself through a pointer, a generic pointer type is
+			// used as placeholder. Pointer values of placeholder type are
+			// converted to the pointer type of their Go type when used (see
+			// Func.setLocal).
+			return irtypes.I8Ptr
+		}
+		// Compile type definitions not yet emitted on demand.
+		return m.emitNamedType(goType)
 	case *gotypes.Pointer:
 		return m.irTypeFromGoPointerType(goType)
 	case *gotypes.Signature:
@@ -167,7 +334,7 @@ func (m *Module) irTypeFromGo(goType gotypes.Type) irtypes.Type {
 	case *gotypes.Struct:
 		return m.irTypeFromGoStructType(goType)
 	case *gotypes.Tuple:
-		panic("support for *gotypes.Tuple not yet implemented")
+		return m.irTypeFromGoTupleType(goType)
 	default:
 		panic(fmt.Errorf("support for Go type %T not yet implemented", goType))
 	}
@@ -234,18 +401,22 @@ func (m *Module) irTypeFromGoBasicType(goType *gotypes.Basic) irtypes.Type {
 	// unsafe pointer types.
 	case gotypes.UnsafePointer:
 		typeName = "unsafe.Pointer"
-	// TODO: figure out how to handle untyped values if/when needed.
+	// untyped types; untyped values are represented using their default type.
 	case gotypes.UntypedBool:
 		typeName = "bool"
 	case gotypes.UntypedInt:
 		typeName = "int"
-	// case gotypes.UntypedRune:
+	case gotypes.UntypedRune:
+		typeName = "int32"
 	case gotypes.UntypedFloat:
 		typeName = "float64"
-	// case gotypes.UntypedComplex:
+	case gotypes.UntypedComplex:
+		typeName = "complex128"
 	case gotypes.UntypedString:
 		typeName = "string"
-	// case gotypes.UntypedNil:
+	// untyped nil has no default type; represent it as an unsafe pointer.
+	case gotypes.UntypedNil:
+		typeName = "unsafe.Pointer"
 	default:
 		panic(fmt.Errorf("support for Go basic type kind %v not yet implemented", kind))
 	}
@@ -256,8 +427,22 @@ func (m *Module) irTypeFromGoBasicType(goType *gotypes.Basic) irtypes.Type {
 
 // irTypeFromGoInterfaceType returns the LLVM IR type corresponding to the given
 // Go interface type, emitting to m.
+//
+// Interface values are represented as a pair of an interface method table
+// (which holds the type descriptor of the dynamic type) and a data pointer.
 func (m *Module) irTypeFromGoInterfaceType(goType *gotypes.Interface) irtypes.Type {
-	panic("not yet implemented")
+	return m.newInterfaceType()
+}
+
+// ~~~ [ map type ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// irTypeFromGoMapType returns the LLVM IR type corresponding to the given Go map
+// type, emitting to m.
+//
+// Map values are represented as pointers to runtime hash maps, which hold the
+// type descriptors of the key and element types; nil maps are nil pointers.
+func (m *Module) irTypeFromGoMapType(goType *gotypes.Map) *irtypes.PointerType {
+	return irtypes.NewPointer(m.irTypeFromName("runtime.hmap"))
 }
 
 // ~~~ [ pointer type ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -273,15 +458,32 @@ func (m *Module) irTypeFromGoPointerType(goType *gotypes.Pointer) *irtypes.Point
 
 // irTypeFromGoSignatureType returns the LLVM IR type corresponding to the given
 // Go function signature type, emitting to m.
-func (m *Module) irTypeFromGoSignatureType(goType *gotypes.Signature) *irtypes.PointerType { //*irtypes.FuncType {
+//
+// Function values are represented as a pair of a code pointer and a context
+// pointer. The code pointer takes the context pointer as its first argument,
+// which points to the variables captured by closures (or is nil for functions
+// without free variables).
+func (m *Module) irTypeFromGoSignatureType(goType *gotypes.Signature) *irtypes.StructType {
 	if goType.Recv() != nil {
 		// TODO: add support for methods; add receiver as first function
 		// parameter.
 		panic("support for methods in Go function signature type not yet implemented")
 	}
+	sig := m.irFuncTypeFromGoSig(goType, irtypes.I8Ptr)
+	return m.newFuncValueType(sig)
+}
+
+// irFuncTypeFromGoSig returns the LLVM IR function type corresponding to the
+// given Go function signature, emitting to m. The receiver of the Go function
+// signature is ignored; instead, recvType (if non-nil) is used as the type of
+// the first parameter of the LLVM IR function type.
+func (m *Module) irFuncTypeFromGoSig(goType *gotypes.Signature, recvType irtypes.Type) *irtypes.FuncType {
 	// Convert Go function parameters to equivalent LLVM IR function parameter
 	// types.
 	var paramTypes []irtypes.Type
+	if recvType != nil {
+		paramTypes = append(paramTypes, recvType)
+	}
 	goParams := goType.Params()
 	for i := 0; i < goParams.Len(); i++ {
 		goParam := goParams.At(i)
@@ -319,10 +521,7 @@ func (m *Module) irTypeFromGoSignatureType(goType *gotypes.Signature) *irtypes.P
 	// Generate LLVM IR function signature type.
 	sig := irtypes.NewFunc(retType, paramTypes...)
 	sig.Variadic = goType.Variadic()
-	// TODO: consider how to convert Go function signature types, in LLVM IR,
-	// function values when used as callees are of type pointer to function
-	// signature type.
-	return irtypes.NewPointer(sig)
+	return sig
 }
 
 // ~~~ [ slice type ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -341,11 +540,10 @@ func (m *Module) irTypeFromGoSliceType(goType *gotypes.Slice) *irtypes.StructTyp
 func (m *Module) irTypeFromGoStructType(goType *gotypes.Struct) *irtypes.StructType {
 	var fields []irtypes.Type
 	for i := 0; i < goType.NumFields(); i++ {
+		// Embedded fields are laid out like normal fields; promoted fields are
+		// accessed through chains of field (address) instructions, as selected
+		// by Go SSA.
 		goField := goType.Field(i)
-		if goField.Embedded() {
-			// TODO: add support for embedded types.
-			panic(fmt.Errorf("support for embedded types not yet implemented; struct type has embedded field %q", goField.Name()))
-		}
 		// TODO: add custom LLVM IR struct type which retains the names of struct
 		// fields.
 		name := goField.Name()
@@ -356,14 +554,60 @@ func (m *Module) irTypeFromGoStructType(goType *gotypes.Struct) *irtypes.StructT
 	return irtypes.NewStruct(fields...)
 }
 
-// --- [ index ] ---------------------------------------------------------------
+// ~~~ [ tuple type ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// irTypeFromGoTupleType returns the LLVM IR type corresponding to the given Go
+// tuple type (e.g. the result of a comma-ok type assertion), emitting to m.
+//
+// Tuples are represented as structure types with one field per tuple element,
+// matching the representation of multiple return values.
+func (m *Module) irTypeFromGoTupleType(goType *gotypes.Tuple) *irtypes.StructType {
+	var fields []irtypes.Type
+	for i := 0; i < goType.Len(); i++ {
+		field := m.irTypeFromGo(goType.At(i).Type())
+		fields = append(fields, field)
+	}
+	return irtypes.NewStruct(fields...)
+}
 
-// TODO: remove indexType?
+// --- [ index ] ---------------------------------------------------------------
 
-// indexType indexes the given Go SSA type, creating a corresponding LLVM IR
-// type, emitting to m.
+// indexType indexes the given Go SSA type definition, creating a corresponding
+// LLVM IR type definition, emitting to m.
 func (m *Module) indexType(goType *ssa.Type) error {
-	panic("not yet implemented")
+	dbg.Println("indexType")
+	goNamedType, ok := goType.Type().(*gotypes.Named)
+	if !ok {
+		// Type aliases are compiled by emitType.
+		return nil
+	}
+	m.indexNamedType(goNamedType)
+	return nil
+}
+
+// indexNamedType indexes the given named Go type, emitting to m. Type
+// definitions with underlying structure type (i.e. Go struct, slice, function
+// and interface types) are indexed as opaque LLVM IR structure types, the
+// bodies of which are filled in when compiling the type definition. This way,
+// (mutually) recursive types may refer to each other by name.
+func (m *Module) indexNamedType(goNamedType *gotypes.Named) {
+	typeName := m.fullTypeName(goNamedType)
+	if _, ok := m.types[typeName]; ok {
+		// type definition already present.
+		return
+	}
+	switch goNamedType.Underlying().(type) {
+	case *gotypes.Struct, *gotypes.Slice, *gotypes.Signature, *gotypes.Interface:
+		typ := irtypes.NewStruct()
+		typ.Opaque = true
+		typ.SetName(typeName)
+		dbg.Printf("   typ: %s = type opaque", typ.String())
+		// Note, the type definition is added to the LLVM IR module when its
+		// body is filled in, as LLVM IR only allows forward references to
+		// structure types; thus non-structure type definitions referred to from
+		// its body must precede it.
+		m.types[typ.Name()] = typ
+	}
 }
 
 // --- [ compile ] -------------------------------------------------------------
@@ -372,6 +616,10 @@ func (m *Module) indexType(goType *ssa.Type) error {
 // emitting to m.
 func (m *Module) emitType(goType *ssa.Type) error {
 	dbg.Println("emitType")
+	if goNamedType, ok := goType.Type().(*gotypes.Named); ok {
+		m.emitNamedType(goNamedType)
+		return nil
+	}
 	typeName := m.fullName(goType)
 	dbg.Println("   typeName:", typeName)
 	if _, ok := m.types[typeName]; ok {
@@ -393,6 +641,45 @@ func (m *Module) emitType(goType *ssa.Type) error {
 	return nil
 }
 
+// emitNamedType compiles the type definition of the given named Go type to
+// corresponding LLVM IR, emitting to m. The body of an indexed opaque type
+// definition is filled in, and type definitions not yet indexed are indexed
+// first. Other type definitions referring to themselves (e.g. `type P *P`) use
+// a placeholder type for the recursive reference (see irTypeFromGo).
+func (m *Module) emitNamedType(goNamedType *gotypes.Named) irtypes.Type {
+	dbg.Println("emitNamedType")
+	typeName := m.fullTypeName(goNamedType)
+	dbg.Println("   typeName:", typeName)
+	m.indexNamedType(goNamedType)
+	if typ, ok := m.types[typeName]; ok {
+		t, ok := typ.(*irtypes.StructType)
+		if !ok || !t.Opaque {
+			// type definition already present.
+			return typ
+		}
+		// Fill in body of opaque type definition; any references to the type
+		// definition from within its body refer to the opaque type by name.
+		underlying := m.irTypeFromGo(goNamedType.Underlying()).(*irtypes.StructType)
+		t.Fields = underlying.Fields
+		t.Packed = underlying.Packed
+		t.Opaque = false
+		dbg.Printf("   typ: %s = type %s", t.String(), t.LLString())
+		m.TypeDefs = append(m.TypeDefs, t)
+		return t
+	}
+	m.pendingTypes[typeName] = true
+	underlying := m.irTypeFromGo(goNamedType.Underlying())
+	delete(m.pendingTypes, typeName)
+	// Perform a shallow copy of the underlying type, to not reset the name of a
+	// previously named type.
+	typ := copyTypeShallow(underlying)
+	typ.SetName(typeName)
+	dbg.Printf("   typ: %s = type %s", typ.String(), typ.LLString())
+	m.types[typ.Name()] = typ
+	m.TypeDefs = append(m.TypeDefs, typ)
+	return typ
+}
+
 // --- [ copy ] ----------------------------------------------------------------
 
 // ~~~ [ shallow copy ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
@@ -626,3 +913,34 @@ func (m *Module) newSliceType(elemType irtypes.Type) *irtypes.StructType {
 func (m *Module) getSliceTypeName(elemType irtypes.Type) string {
 	return "[]" + elemType.String() // TODO: use fully qualified name for elemType.
 }
+
+// newInterfaceType returns a new LLVM IR interface type.
+//
+// Pre-condition: initialize predeclared types.
+func (m *Module) newInterfaceType() *irtypes.StructType {
+	// TODO: add support for LLVM IR structure types with field names.
+	//interfaceType = NewStruct(
+	//   Field{Name: "tab", Type: irtypes.NewPointer(itabType)},
+	//   Field{Name: "data", Type: irtypes.I8Ptr}, // generic pointer type
+	//)
+	itabType := m.irTypeFromName("runtime.itab")
+	return irtypes.NewStruct(
+		irtypes.NewPointer(itabType),
+		irtypes.I8Ptr, // generic pointer type
+	)
+}
+
+// newFuncValueType returns a new LLVM IR function value type, based on the
+// given LLVM IR function signature of the code pointer (which takes the context
+// pointer as first argument).
+func (m *Module) newFuncValueType(sig *irtypes.FuncType) *irtypes.StructType {
+	// TODO: add support for LLVM IR structure types with field names.
+	//funcValueType = NewStruct(
+	//   Field{Name: "code", Type: irtypes.NewPointer(sig)},
+	//   Field{Name: "ctx", Type: irtypes.I8Ptr}, // generic pointer type
+	//)
+	return irtypes.NewStruct(
+		irtypes.NewPointer(sig),
+		irtypes.I8Ptr, // generic pointer type
+	)
+}
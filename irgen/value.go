@@ -3,8 +3,8 @@ package irgen
 import (
 	"fmt"
 	goconstant "go/constant"
+	gotypes "go/types"
 	"math/big"
-	"strconv"
 
 	"github.com/llir/llvm/ir"
 	irconstant "github.com/llir/llvm/ir/constant"
@@ -40,7 +40,13 @@ func (fn *Func) irValueFromGo(goValue ssa.Value) irvalue.Value {
 	// Translate local or global Go SSA value.
 	switch goValue := goValue.(type) {
 	case *ssa.FreeVar:
-		panic("support for *ssa.FreeVar not yet implemented")
+		// Lookup indexed LLVM IR value of Go SSA free variable.
+		if v, ok := fn.locals[goValue]; ok {
+			return v
+		}
+		// Pre-condition invalidated, free variable should have been indexed. This
+		// is a fatal error and indicates a bug in irgen.
+		panic(fmt.Errorf("unable to locate indexed LLVM IR value of Go SSA free variable %q", goValue.Name()))
 	case *ssa.Parameter:
 		// Lookup indexed LLVM IR function parameter of Go SSA function parameter.
 		if v, ok := fn.locals[goValue]; ok {
@@ -66,6 +72,10 @@ func (fn *Func) irValueFromGo(goValue ssa.Value) irvalue.Value {
 // irValueFromGo returns the LLVM IR value corresponding to the given global Go
 // SSA value, emitting to m.
 func (m *Module) irValueFromGo(goValue ssa.Value) irvalue.Value {
+	// Functions used as values are converted to function values.
+	if goFunc, ok := goValue.(*ssa.Function); ok {
+		return m.getFuncValue(goFunc)
+	}
 	// Lookup indexed LLVM IR value of global Go SSA value.
 	if v, ok := m.globals[goValue]; ok {
 		return v
@@ -76,8 +86,6 @@ func (m *Module) irValueFromGo(goValue ssa.Value) irvalue.Value {
 		return m.irValueFromGoBuiltin(goValue)
 	case *ssa.Const:
 		return m.irValueFromGoConst(goValue)
-	case *ssa.Function:
-		return m.irValueFromGoFunc(goValue)
 	case *ssa.Global:
 		warn.Printf("unable to locate LLVM IR value of global Go value %q", m.fullName(goValue))
 		panic("support for *ssa.Global not yet implemented")
@@ -107,93 +115,144 @@ func (m *Module) irValueFromGoConst(goConst *ssa.Const) irconstant.Constant {
 	dbg.Println("irValueFromGoConst")
 	typ := m.irTypeFromGo(goConst.Type())
 	dbg.Println("   typ:", typ)
+	if goConst.IsNil() {
+		switch goType := goConst.Type().Underlying().(type) {
+		// nil interface value, nil function value, nil map, nil pointer, nil
+		// slice or nil channel.
+		case *gotypes.Interface, *gotypes.Signature, *gotypes.Map, *gotypes.Pointer, *gotypes.Slice, *gotypes.Chan:
+			return irconstant.NewZeroInitializer(typ)
+		// nil unsafe pointer or untyped nil.
+		case *gotypes.Basic:
+			if goType.Kind() != gotypes.UnsafePointer && goType.Kind() != gotypes.UntypedNil {
+				panic(fmt.Errorf("support for nil constant of Go type %v not yet implemented", goConst.Type()))
+			}
+			return irconstant.NewZeroInitializer(typ)
+		default:
+			panic(fmt.Errorf("support for nil constant of Go type %v not yet implemented", goConst.Type()))
+		}
+	}
+	if isComplexType(goConst.Type()) {
+		// Constants of complex type may be of integer, floating-point or complex
+		// kind.
+		return irValueFromGoComplexLit(typ.(*irtypes.StructType), goConst.Value)
+	}
 	goVal := goconstant.Val(goConst.Value)
 	dbg.Println("   goVal:", goVal)
 	switch goVal := goVal.(type) {
 	// boolean literal
 	case bool:
-		return irconstant.NewBool(goVal)
+		// Use the LLVM IR type of the constant, as it may be of defined boolean
+		// type.
+		x := big.NewInt(0)
+		if goVal {
+			x.SetInt64(1)
+		}
+		return &irconstant.Int{
+			Typ: typ.(*irtypes.IntType),
+			X:   x,
+		}
 	// string literal
 	case string:
-		return m.irValueFromGoStringLit(typ, goVal)
+		return m.irValueFromGoStringLit(goConst.Type(), goVal)
 	// integer literal
 	case int64:
 		switch typ := typ.(type) {
 		case *irtypes.IntType:
 			return irconstant.NewInt(typ, goVal)
 		case *irtypes.FloatType:
-			s := strconv.FormatInt(goVal, 10) + ".0"
-			c, err := irconstant.NewFloatFromString(typ, s)
-			if err != nil {
-				panic(fmt.Errorf("unable to parse floating-point literal %q", s))
-			}
-			return c
+			return irValueFromGoFloatLit(typ, goConst.Value)
 		default:
 			panic(fmt.Errorf("support for integer literal of type %T not yet implemented", typ))
 		}
 	case *big.Int:
-		x := big.NewInt(0).Set(goVal)
-		return &irconstant.Int{
-			Typ: typ.(*irtypes.IntType),
-			X:   x,
+		// Integer constants out of range of int64; e.g. uint64 constants of at
+		// least 1<<63, or (untyped) integer constants converted to
+		// floating-point type.
+		switch typ := typ.(type) {
+		case *irtypes.IntType:
+			x := big.NewInt(0).Set(goVal)
+			return &irconstant.Int{
+				Typ: typ,
+				X:   x,
+			}
+		case *irtypes.FloatType:
+			return irValueFromGoFloatLit(typ, goConst.Value)
+		default:
+			panic(fmt.Errorf("support for integer literal of type %T not yet implemented", typ))
 		}
 	// floating-point literal
-	case *big.Rat:
-		floatType := typ.(*irtypes.FloatType)
-		prec := precFromFloatKind(floatType.Kind)
-		x := big.NewFloat(0).SetPrec(prec)
-		x.SetRat(goVal)
-		return &irconstant.Float{
-			Typ: floatType,
-			X:   x,
-		}
-	case *big.Float:
-		floatType := typ.(*irtypes.FloatType)
-		prec := precFromFloatKind(floatType.Kind)
-		x := big.NewFloat(0).SetPrec(prec)
-		x.Set(goVal)
-		return &irconstant.Float{
-			Typ: floatType,
-			X:   x,
-		}
+	case *big.Rat, *big.Float:
+		return irValueFromGoFloatLit(typ.(*irtypes.FloatType), goConst.Value)
 	// kind of everything else is nil
 	case nil:
-		// Check constant kind for nil values, as go/constant.Val returns nil also
-		// for complex constant literals.
-		switch kind := goConst.Value.Kind(); kind {
-		default:
-			panic(fmt.Errorf("support for Go constant kind %v not yet implemented", kind))
-		}
+		// go/constant.Val returns nil for complex constants (handled above) and
+		// unknown constants, the latter of which are only produced for erroneous
+		// programs.
+		panic(fmt.Errorf("support for Go constant kind %v not yet implemented", goConst.Value.Kind()))
 	default:
 		panic(fmt.Errorf("support for Go constant %T not yet implemented", goVal))
 	}
 }
 
+// ~~~ [ floating-point literal ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// irValueFromGoFloatLit returns the LLVM IR constant corresponding to the given
+// Go constant of integer or floating-point kind, as a floating-point constant of
+// the specified LLVM IR floating-point type.
+//
+// The constant is rounded to the nearest value representable by the type, as
+// done by gc; in particular, values of type float32 are rounded once from the
+// exact value (not by way of float64), values too small in magnitude round to
+// (signed) zero or denormals, and values too large in magnitude round to
+// infinity.
+func irValueFromGoFloatLit(typ *irtypes.FloatType, v goconstant.Value) *irconstant.Float {
+	var x float64
+	switch typ.Kind {
+	case irtypes.FloatKindFloat:
+		f, _ := goconstant.Float32Val(v)
+		x = float64(f)
+	case irtypes.FloatKindDouble:
+		x, _ = goconstant.Float64Val(v)
+	default:
+		panic(fmt.Errorf("support for floating-point kind %v not yet implemented", typ.Kind))
+	}
+	// NaN, infinities and negative zero are handled by irconstant.NewFloat.
+	return irconstant.NewFloat(typ, x)
+}
+
+// ~~~ [ complex literal ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// irValueFromGoComplexLit returns the LLVM IR constant corresponding to the
+// given Go constant of numeric kind, as a complex constant of the specified
+// LLVM IR complex type. The real and imaginary parts are rounded separately.
+func irValueFromGoComplexLit(typ *irtypes.StructType, v goconstant.Value) *irconstant.Struct {
+	partType := typ.Fields[0].(*irtypes.FloatType)
+	real := irValueFromGoFloatLit(partType, goconstant.Real(v))
+	imag := irValueFromGoFloatLit(partType, goconstant.Imag(v))
+	return irconstant.NewStruct(typ, real, imag)
+}
+
 // ~~~ [ string literal ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // irValueFromGoStringLit returns the LLVM IR constant corresponding to the
-// given Go string literal, emitting to m.
-func (m *Module) irValueFromGoStringLit(typ irtypes.Type, s string) irconstant.Constant {
-	switch typ.Name() {
-	case "string":
-		g := m.emitStringLit(s)
-		strLit := g.Init.(*irconstant.CharArray)
-		n := int64(len(strLit.X))
-		// Unpack %string type.
-		stringType := typ.(*irtypes.StructType)
-		dataType := stringType.Fields[0].(*irtypes.PointerType)
-		_ = dataType
-		lenType := stringType.Fields[1].(*irtypes.IntType)
-		// Create data field.
-		zero := irconstant.NewInt(irtypes.I64, 0)
-		data := irconstant.NewGetElementPtr(strLit.Typ, g, zero, zero)
-		// Create len field.
-		length := irconstant.NewInt(lenType, n)
-		// Return LLVM IR string constant.
-		return irconstant.NewStruct(stringType, data, length)
-	default:
-		panic(fmt.Errorf("support for converting Go string literal to LLVM IR constant with LLVM IR type %T (%q) not yet implemented", typ, typ.Name()))
+// given Go string literal of the specified Go type, emitting to m.
+func (m *Module) irValueFromGoStringLit(goType gotypes.Type, s string) irconstant.Constant {
+	if !isStringType(goType) {
+		panic(fmt.Errorf("support for converting Go string literal to LLVM IR constant of Go type %v not yet implemented", goType))
 	}
+	g := m.emitStringLit(s)
+	strLit := g.Init.(*irconstant.CharArray)
+	n := int64(len(strLit.X))
+	// Unpack %string type.
+	stringType := m.irTypeFromGo(goType).(*irtypes.StructType)
+	lenType := stringType.Fields[1].(*irtypes.IntType)
+	// Create data field.
+	zero := irconstant.NewInt(irtypes.I64, 0)
+	data := irconstant.NewGetElementPtr(strLit.Typ, g, zero, zero)
+	// Create len field.
+	length := irconstant.NewInt(lenType, n)
+	// Return LLVM IR string constant.
+	return irconstant.NewStruct(stringType, data, length)
 }
 
 // --- [ function ] ------------------------------------------------------------
@@ -202,5 +261,11 @@ func (m *Module) irValueFromGoStringLit(typ irtypes.Type, s string) irconstant.C
 // SSA function, emitting to m.
 func (m *Module) irValueFromGoFunc(goFunc *ssa.Function) *ir.Func {
 	dbg.Println("irValueFromGoFunc")
+	if _, ok := m.globals[goFunc]; !ok && len(goFunc.Synthetic) > 0 {
+		// Index synthetic functions (e.g. method wrappers) on demand.
+		if err := m.indexSynthFunc(goFunc); err != nil {
+			panic(fmt.Errorf("unable to index synthetic function %q; %+v", m.fullName(goFunc), err))
+		}
+	}
 	return m.getFunc(goFunc)
 }
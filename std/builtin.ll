@@ -16,12 +16,27 @@
 %complex128 = type { %float64, %float64 }
 %string = type { i8*, %int }
 %unsafe.Pointer = type i8*
+%runtime.method = type { %string, i8* }
+%runtime._type = type { %string, %int, %uint8, i1 (i8*, i8*)*, %uint64 (i8*, %uint64)*, %runtime.method*, %int }
+%runtime.itab = type { %runtime._type*, %runtime._type*, [0 x i8*] }
+%runtime.mapentry = type { %runtime.mapentry*, %uint64, %bool }
+%runtime.hmap = type { %int, %int, %runtime.mapentry**, %runtime._type*, %runtime._type*, %uint64 }
+%runtime.hiter = type { %runtime.hmap*, %runtime.mapentry**, %int, %int }
+%"[]%uint8" = type { %uint8*, %int, %int }
+%"[]%int32" = type { %int32*, %int, %int }
+%"[]%string" = type { %string*, %int, %int }
+%runtime.slice = type { i8*, %int, %int }
+%runtime._defer = type { %runtime._defer*, void (i8*)*, i8*, i8* }
+%runtime._panic = type { %runtime._panic*, %runtime.itab*, i8*, %bool, %bool }
 
 @builtin.newline = global [1 x i8] c"\0A"
 
 ; ssize_t write(int fildes, const void *buf, size_t nbyte)
 declare i64 @write(i64 %fd, i8* %buf, i64 %n)
 
+; void *calloc(size_t nmemb, size_t size)
+declare i8* @calloc(i64 %nmemb, i64 %size)
+
 ; func println(args ...Type)
 ;
 ;    The println built-in function formats its arguments in an
@@ -62,16 +77,36 @@ define void @print(%string %s, ...) {
 ; (For use in indirection wrappers.)
 ;
 ;    func ssa:wrapnilchk(ptr *T, recvType, methodName string) *T
+;
+;       panic: value method main.T.M called using nil *T pointer
 define i8* @"ssa:wrapnilchk"(i8* %ptr, %string %recvType, %string %methodName) {
 	%ptr_val = ptrtoint i8* %ptr to %uintptr
 	%is_null = icmp eq %uintptr %ptr_val, 0
-	br i1 %is_null, label %success, label %fail
+	br i1 %is_null, label %fail, label %success
 
 success:
 	ret i8* %ptr
 
 fail:
-	; TODO: add panic message with "recvType.methodName".
+	; Receiver type qualified by package name (e.g. "main.T"), and without
+	; package name (e.g. "T").
+	%slash = call %int @runtime.lastindexbyte(%string %recvType, i8 47) ; '/'
+	%qualified_start = add %int %slash, 1
+	%qualified = call %string @runtime.slicestring(%string %recvType, %int %qualified_start)
+	%dot = call %int @runtime.lastindexbyte(%string %qualified, i8 46) ; '.'
+	%name_start = add %int %dot, 1
+	%name = call %string @runtime.slicestring(%string %qualified, %int %name_start)
+	%value_method = load %string, %string* @"runtime.str.value_method"
+	%dot_str = load %string, %string* @"runtime.str.dot"
+	%called_using_nil = load %string, %string* @"runtime.str.called_using_nil"
+	%pointer = load %string, %string* @"runtime.str.pointer"
+	%msg.0 = call %string @runtime.concatstring2(%string %value_method, %string %qualified)
+	%msg.1 = call %string @runtime.concatstring2(%string %msg.0, %string %dot_str)
+	%msg.2 = call %string @runtime.concatstring2(%string %msg.1, %string %methodName)
+	%msg.3 = call %string @runtime.concatstring2(%string %msg.2, %string %called_using_nil)
+	%msg.4 = call %string @runtime.concatstring2(%string %msg.3, %string %name)
+	%msg = call %string @runtime.concatstring2(%string %msg.4, %string %pointer)
+	call void @runtime.panicerror(%string %msg)
 	unreachable
 }
 
@@ -156,3 +191,3172 @@ x_min:
 y_min:
 	ret %int %y
 }
+
+; func runtime.ifacedataptr(t *_type, data *unsafe.Pointer) unsafe.Pointer
+;
+;    ifacedataptr returns a pointer to the value of type t held by an interface
+;    value with the given data pointer (stored at data). Values of pointer
+;    shaped types are stored directly in the data pointer of interface values.
+define i8* @runtime.ifacedataptr(%runtime._type* %t, i8** %data) {
+entry:
+	%kind_ptr = getelementptr %runtime._type, %runtime._type* %t, i64 0, i32 2
+	%kind = load %uint8, %uint8* %kind_ptr
+	%direct_bit = and %uint8 %kind, 32 ; kindDirectIface
+	%direct = icmp ne %uint8 %direct_bit, 0
+	br i1 %direct, label %ret_direct, label %ret_indirect
+
+ret_direct:
+	%ptr = bitcast i8** %data to i8*
+	ret i8* %ptr
+
+ret_indirect:
+	%value_ptr = load i8*, i8** %data
+	ret i8* %value_ptr
+}
+
+; func runtime.ifaceeq(xTab *itab, xData unsafe.Pointer, yTab *itab, yData unsafe.Pointer) bool
+;
+;    ifaceeq reports whether the interface values x and y are equal. Two
+;    interface values are equal if they are both nil, or if they hold the same
+;    dynamic type and equal dynamic values.
+define i1 @runtime.ifaceeq(%runtime.itab* %x_tab, i8* %x_data, %runtime.itab* %y_tab, i8* %y_data) {
+entry:
+	%x_data_addr = alloca i8*
+	store i8* %x_data, i8** %x_data_addr
+	%y_data_addr = alloca i8*
+	store i8* %y_data, i8** %y_data_addr
+	%x_nil = icmp eq %runtime.itab* %x_tab, null
+	%y_nil = icmp eq %runtime.itab* %y_tab, null
+	%any_nil = or i1 %x_nil, %y_nil
+	br i1 %any_nil, label %check_nil, label %check_type
+
+check_nil:
+	%both_nil = and i1 %x_nil, %y_nil
+	ret i1 %both_nil
+
+check_type:
+	%x_type_ptr = getelementptr %runtime.itab, %runtime.itab* %x_tab, i64 0, i32 1
+	%x_type = load %runtime._type*, %runtime._type** %x_type_ptr
+	%y_type_ptr = getelementptr %runtime.itab, %runtime.itab* %y_tab, i64 0, i32 1
+	%y_type = load %runtime._type*, %runtime._type** %y_type_ptr
+	%same_type = icmp eq %runtime._type* %x_type, %y_type
+	br i1 %same_type, label %check_comparable, label %ret_false
+
+check_comparable:
+	%equal_ptr = getelementptr %runtime._type, %runtime._type* %x_type, i64 0, i32 3
+	%equal = load i1 (i8*, i8*)*, i1 (i8*, i8*)** %equal_ptr
+	%uncomparable = icmp eq i1 (i8*, i8*)* %equal, null
+	br i1 %uncomparable, label %fail, label %check_value
+
+check_value:
+	%x_ptr = call i8* @runtime.ifacedataptr(%runtime._type* %x_type, i8** %x_data_addr)
+	%y_ptr = call i8* @runtime.ifacedataptr(%runtime._type* %x_type, i8** %y_data_addr)
+	%result = call i1 %equal(i8* %x_ptr, i8* %y_ptr)
+	ret i1 %result
+
+ret_false:
+	ret i1 false
+
+fail:
+	call void @runtime.panicuncomparable(%runtime._type* %x_type)
+	unreachable
+}
+
+; === [ runtime panics ] =======================================================
+
+; void exit(int status)
+declare void @exit(i32 %status)
+
+; void longjmp(jmp_buf env, int val)
+declare void @longjmp(i8* %env, i32 %val) noreturn
+
+@"runtime.str.panic.data" = private unnamed_addr constant [7 x i8] c"panic: "
+@"runtime.str.panic" = private unnamed_addr constant %string { i8* getelementptr ([7 x i8], [7 x i8]* @"runtime.str.panic.data", i64 0, i64 0), %int 7 }
+@"runtime.str.goroutine.data" = private unnamed_addr constant [24 x i8] c"\0Agoroutine 1 [running]:\0A"
+@"runtime.str.goroutine" = private unnamed_addr constant %string { i8* getelementptr ([24 x i8], [24 x i8]* @"runtime.str.goroutine.data", i64 0, i64 0), %int 24 }
+@"runtime.str.tab.data" = private unnamed_addr constant [1 x i8] c"\09"
+@"runtime.str.tab" = private unnamed_addr constant %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.tab.data", i64 0, i64 0), %int 1 }
+@"runtime.str.recovered.data" = private unnamed_addr constant [12 x i8] c" [recovered]"
+@"runtime.str.recovered" = private unnamed_addr constant %string { i8* getelementptr ([12 x i8], [12 x i8]* @"runtime.str.recovered.data", i64 0, i64 0), %int 12 }
+@"runtime.str.recovered_repanicked.data" = private unnamed_addr constant [24 x i8] c" [recovered, repanicked]"
+@"runtime.str.recovered_repanicked" = private unnamed_addr constant %string { i8* getelementptr ([24 x i8], [24 x i8]* @"runtime.str.recovered_repanicked.data", i64 0, i64 0), %int 24 }
+@"runtime.str.newline.data" = private unnamed_addr constant [1 x i8] c"\0A"
+@"runtime.str.newline" = private unnamed_addr constant %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.newline.data", i64 0, i64 0), %int 1 }
+@"runtime.str.nil_arg.data" = private unnamed_addr constant [30 x i8] c"panic called with nil argument"
+@"runtime.str.nil_arg" = private unnamed_addr constant %string { i8* getelementptr ([30 x i8], [30 x i8]* @"runtime.str.nil_arg.data", i64 0, i64 0), %int 30 }
+@"runtime.str.error_key.data" = private unnamed_addr constant [19 x i8] c"Error func() string"
+@"runtime.str.error_key" = private unnamed_addr constant %string { i8* getelementptr ([19 x i8], [19 x i8]* @"runtime.str.error_key.data", i64 0, i64 0), %int 19 }
+@"runtime.str.string_key.data" = private unnamed_addr constant [20 x i8] c"String func() string"
+@"runtime.str.string_key" = private unnamed_addr constant %string { i8* getelementptr ([20 x i8], [20 x i8]* @"runtime.str.string_key.data", i64 0, i64 0), %int 20 }
+@"runtime.str.runtime_error_key.data" = private unnamed_addr constant [19 x i8] c"RuntimeError func()"
+@"runtime.str.plainError.data" = private unnamed_addr constant [18 x i8] c"runtime.plainError"
+@"runtime.str.lparen.data" = private unnamed_addr constant [1 x i8] c"("
+@"runtime.str.lparen" = private unnamed_addr constant %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.lparen.data", i64 0, i64 0), %int 1 }
+@"runtime.str.lparen_quote.data" = private unnamed_addr constant [2 x i8] c"(\22"
+@"runtime.str.lparen_quote" = private unnamed_addr constant %string { i8* getelementptr ([2 x i8], [2 x i8]* @"runtime.str.lparen_quote.data", i64 0, i64 0), %int 2 }
+@"runtime.str.rparen.data" = private unnamed_addr constant [1 x i8] c")"
+@"runtime.str.rparen" = private unnamed_addr constant %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.rparen.data", i64 0, i64 0), %int 1 }
+@"runtime.str.quote_rparen.data" = private unnamed_addr constant [2 x i8] c"\22)"
+@"runtime.str.quote_rparen" = private unnamed_addr constant %string { i8* getelementptr ([2 x i8], [2 x i8]* @"runtime.str.quote_rparen.data", i64 0, i64 0), %int 2 }
+@"runtime.str.rparen_space.data" = private unnamed_addr constant [2 x i8] c") "
+@"runtime.str.rparen_space" = private unnamed_addr constant %string { i8* getelementptr ([2 x i8], [2 x i8]* @"runtime.str.rparen_space.data", i64 0, i64 0), %int 2 }
+@"runtime.str.true.data" = private unnamed_addr constant [4 x i8] c"true"
+@"runtime.str.true" = private unnamed_addr constant %string { i8* getelementptr ([4 x i8], [4 x i8]* @"runtime.str.true.data", i64 0, i64 0), %int 4 }
+@"runtime.str.false.data" = private unnamed_addr constant [5 x i8] c"false"
+@"runtime.str.false" = private unnamed_addr constant %string { i8* getelementptr ([5 x i8], [5 x i8]* @"runtime.str.false.data", i64 0, i64 0), %int 5 }
+@"runtime.str.0x.data" = private unnamed_addr constant [2 x i8] c"0x"
+@"runtime.str.0x" = private unnamed_addr constant %string { i8* getelementptr ([2 x i8], [2 x i8]* @"runtime.str.0x.data", i64 0, i64 0), %int 2 }
+@"runtime.str.iface_conv.data" = private unnamed_addr constant [22 x i8] c"interface conversion: "
+@"runtime.str.iface_conv" = private unnamed_addr constant %string { i8* getelementptr ([22 x i8], [22 x i8]* @"runtime.str.iface_conv.data", i64 0, i64 0), %int 22 }
+@"runtime.str.interface.data" = private unnamed_addr constant [9 x i8] c"interface"
+@"runtime.str.interface" = private unnamed_addr constant %string { i8* getelementptr ([9 x i8], [9 x i8]* @"runtime.str.interface.data", i64 0, i64 0), %int 9 }
+@"runtime.str.nil.data" = private unnamed_addr constant [3 x i8] c"nil"
+@"runtime.str.nil" = private unnamed_addr constant %string { i8* getelementptr ([3 x i8], [3 x i8]* @"runtime.str.nil.data", i64 0, i64 0), %int 3 }
+@"runtime.str.is.data" = private unnamed_addr constant [4 x i8] c" is "
+@"runtime.str.is" = private unnamed_addr constant %string { i8* getelementptr ([4 x i8], [4 x i8]* @"runtime.str.is.data", i64 0, i64 0), %int 4 }
+@"runtime.str.not.data" = private unnamed_addr constant [6 x i8] c", not "
+@"runtime.str.not" = private unnamed_addr constant %string { i8* getelementptr ([6 x i8], [6 x i8]* @"runtime.str.not.data", i64 0, i64 0), %int 6 }
+@"runtime.str.is_not.data" = private unnamed_addr constant [8 x i8] c" is not "
+@"runtime.str.is_not" = private unnamed_addr constant %string { i8* getelementptr ([8 x i8], [8 x i8]* @"runtime.str.is_not.data", i64 0, i64 0), %int 8 }
+@"runtime.str.missing_method.data" = private unnamed_addr constant [17 x i8] c": missing method "
+@"runtime.str.missing_method" = private unnamed_addr constant %string { i8* getelementptr ([17 x i8], [17 x i8]* @"runtime.str.missing_method.data", i64 0, i64 0), %int 17 }
+@"runtime.str.runtime_error.data" = private unnamed_addr constant [15 x i8] c"runtime error: "
+@"runtime.str.runtime_error" = private unnamed_addr constant %string { i8* getelementptr ([15 x i8], [15 x i8]* @"runtime.str.runtime_error.data", i64 0, i64 0), %int 15 }
+@"runtime.str.uncomparable.data" = private unnamed_addr constant [28 x i8] c"comparing uncomparable type "
+@"runtime.str.uncomparable" = private unnamed_addr constant %string { i8* getelementptr ([28 x i8], [28 x i8]* @"runtime.str.uncomparable.data", i64 0, i64 0), %int 28 }
+@"runtime.str.unhashable.data" = private unnamed_addr constant [24 x i8] c"hash of unhashable type "
+@"runtime.str.unhashable" = private unnamed_addr constant %string { i8* getelementptr ([24 x i8], [24 x i8]* @"runtime.str.unhashable.data", i64 0, i64 0), %int 24 }
+@"runtime.str.nil_map.data" = private unnamed_addr constant [30 x i8] c"assignment to entry in nil map"
+@"runtime.str.nil_map" = private unnamed_addr constant %string { i8* getelementptr ([30 x i8], [30 x i8]* @"runtime.str.nil_map.data", i64 0, i64 0), %int 30 }
+@"runtime.str.makeslice_len.data" = private unnamed_addr constant [27 x i8] c"makeslice: len out of range"
+@"runtime.str.makeslice_len" = private unnamed_addr constant %string { i8* getelementptr ([27 x i8], [27 x i8]* @"runtime.str.makeslice_len.data", i64 0, i64 0), %int 27 }
+@"runtime.str.makeslice_cap.data" = private unnamed_addr constant [27 x i8] c"makeslice: cap out of range"
+@"runtime.str.makeslice_cap" = private unnamed_addr constant %string { i8* getelementptr ([27 x i8], [27 x i8]* @"runtime.str.makeslice_cap.data", i64 0, i64 0), %int 27 }
+
+@"runtime.str.plus.data" = private unnamed_addr constant [1 x i8] c"+"
+@"runtime.str.plus" = private unnamed_addr constant %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.plus.data", i64 0, i64 0), %int 1 }
+@"runtime.str.imag_rparen.data" = private unnamed_addr constant [2 x i8] c"i)"
+@"runtime.str.imag_rparen" = private unnamed_addr constant %string { i8* getelementptr ([2 x i8], [2 x i8]* @"runtime.str.imag_rparen.data", i64 0, i64 0), %int 2 }
+@"runtime.str.nan.data" = private unnamed_addr constant [3 x i8] c"NaN"
+@"runtime.str.nan" = private unnamed_addr constant %string { i8* getelementptr ([3 x i8], [3 x i8]* @"runtime.str.nan.data", i64 0, i64 0), %int 3 }
+@"runtime.str.pos_inf.data" = private unnamed_addr constant [4 x i8] c"+Inf"
+@"runtime.str.pos_inf" = private unnamed_addr constant %string { i8* getelementptr ([4 x i8], [4 x i8]* @"runtime.str.pos_inf.data", i64 0, i64 0), %int 4 }
+@"runtime.str.neg_inf.data" = private unnamed_addr constant [4 x i8] c"-Inf"
+@"runtime.str.neg_inf" = private unnamed_addr constant %string { i8* getelementptr ([4 x i8], [4 x i8]* @"runtime.str.neg_inf.data", i64 0, i64 0), %int 4 }
+@"runtime.str.value_method.data" = private unnamed_addr constant [13 x i8] c"value method "
+@"runtime.str.value_method" = private unnamed_addr constant %string { i8* getelementptr ([13 x i8], [13 x i8]* @"runtime.str.value_method.data", i64 0, i64 0), %int 13 }
+@"runtime.str.dot.data" = private unnamed_addr constant [1 x i8] c"."
+@"runtime.str.dot" = private unnamed_addr constant %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.dot.data", i64 0, i64 0), %int 1 }
+@"runtime.str.called_using_nil.data" = private unnamed_addr constant [19 x i8] c" called using nil *"
+@"runtime.str.called_using_nil" = private unnamed_addr constant %string { i8* getelementptr ([19 x i8], [19 x i8]* @"runtime.str.called_using_nil.data", i64 0, i64 0), %int 19 }
+@"runtime.str.pointer.data" = private unnamed_addr constant [8 x i8] c" pointer"
+@"runtime.str.pointer" = private unnamed_addr constant %string { i8* getelementptr ([8 x i8], [8 x i8]* @"runtime.str.pointer.data", i64 0, i64 0), %int 8 }
+
+; Stack of the active panics of the goroutine; a linked list of panic records,
+; most recent panic first.
+@runtime.panics = global %runtime._panic* null
+
+; Panic recoverable by the deferred function being called by a panic; taken (and
+; cleared) by the deferred function on entry, so that only the deferred function
+; itself may recover the panic.
+@runtime.recoverpanic = global %runtime._panic* null
+
+; func runtime.gopanic(tab *itab, data unsafe.Pointer)
+;
+;    gopanic implements the predeclared function panic. It panics with the empty
+;    interface value with interface method table tab and data pointer data. The
+;    pending deferred calls of the goroutine are run, most recently deferred
+;    first; if a deferred call recovers the panic, execution resumes in the
+;    function which deferred the call, by a long jump to its frame (see
+;    runtime.deferproc). Otherwise, the panic message is written to standard
+;    error after all deferred calls have been run, as done by gc. As done by gc
+;    since Go 1.21, panic(nil) panics with a runtime error.
+;
+;       panic: something bad happened
+define void @runtime.gopanic(%runtime.itab* %tab, i8* %data) {
+entry:
+	%is_nil = icmp eq %runtime.itab* %tab, null
+	br i1 %is_nil, label %panic_nil, label %push
+
+panic_nil:
+	%runtime_error = load %string, %string* @"runtime.str.runtime_error"
+	%nil_arg = load %string, %string* @"runtime.str.nil_arg"
+	%msg = call %string @runtime.concatstring2(%string %runtime_error, %string %nil_arg)
+	call void @runtime.panicerror(%string %msg)
+	unreachable
+
+	; Push panic record to the panic stack.
+push:
+	%p = alloca %runtime._panic
+	%link = load %runtime._panic*, %runtime._panic** @runtime.panics
+	%link_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 0
+	store %runtime._panic* %link, %runtime._panic** %link_ptr
+	%tab_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 1
+	store %runtime.itab* %tab, %runtime.itab** %tab_ptr
+	%data_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 2
+	store i8* %data, i8** %data_ptr
+	%recovered_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 3
+	store %bool false, %bool* %recovered_ptr
+	%repanicked_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 4
+	store %bool false, %bool* %repanicked_ptr
+	store %runtime._panic* %p, %runtime._panic** @runtime.panics
+	br label %loop
+
+	; Run pending deferred calls; defer records are popped before their deferred
+	; call is run.
+loop:
+	%d = load %runtime._defer*, %runtime._defer** @runtime.defers
+	%done = icmp eq %runtime._defer* %d, null
+	br i1 %done, label %fatal, label %call
+
+call:
+	%d_link_ptr = getelementptr %runtime._defer, %runtime._defer* %d, i64 0, i32 0
+	%d_link = load %runtime._defer*, %runtime._defer** %d_link_ptr
+	store %runtime._defer* %d_link, %runtime._defer** @runtime.defers
+	%fn_ptr = getelementptr %runtime._defer, %runtime._defer* %d, i64 0, i32 1
+	%fn = load void (i8*)*, void (i8*)** %fn_ptr
+	%arg_ptr = getelementptr %runtime._defer, %runtime._defer* %d, i64 0, i32 2
+	%arg = load i8*, i8** %arg_ptr
+	%saved = load %runtime._panic*, %runtime._panic** @runtime.recoverpanic
+	store %runtime._panic* %p, %runtime._panic** @runtime.recoverpanic
+	call void %fn(i8* %arg)
+	store %runtime._panic* %saved, %runtime._panic** @runtime.recoverpanic
+	%recovered = load %bool, %bool* %recovered_ptr
+	br i1 %recovered, label %recover, label %loop
+
+	; Pop the panics started by frames below the frame which deferred the call
+	; (including p), and resume execution in that frame.
+recover:
+	%frame_ptr = getelementptr %runtime._defer, %runtime._defer* %d, i64 0, i32 3
+	%frame = load i8*, i8** %frame_ptr
+	%frame_addr = ptrtoint i8* %frame to i64
+	br label %unwind
+
+unwind:
+	%top = load %runtime._panic*, %runtime._panic** @runtime.panics
+	%top_addr = ptrtoint %runtime._panic* %top to i64
+	%below = icmp ult i64 %top_addr, %frame_addr
+	%has_top = icmp ne %runtime._panic* %top, null
+	%pop = and i1 %has_top, %below
+	br i1 %pop, label %unwind_pop, label %resume
+
+unwind_pop:
+	%top_link_ptr = getelementptr %runtime._panic, %runtime._panic* %top, i64 0, i32 0
+	%top_link = load %runtime._panic*, %runtime._panic** %top_link_ptr
+	store %runtime._panic* %top_link, %runtime._panic** @runtime.panics
+	br label %unwind
+
+resume:
+	call void @longjmp(i8* %frame, i32 1)
+	unreachable
+
+fatal:
+	call void @runtime.fatalpanic(%runtime._panic* %p)
+	unreachable
+}
+
+; func runtime.gorecover(p *_panic) interface{}
+;
+;    gorecover implements the predeclared function recover. It stops the panic p,
+;    as taken by the calling function on entry (see runtime.recoverpanic), and
+;    returns its panic value. If p is nil (i.e. the caller is not a deferred
+;    function called by a panic), or if p has already been recovered, gorecover
+;    returns nil.
+define { %runtime.itab*, i8* } @runtime.gorecover(%runtime._panic* %p) {
+entry:
+	%is_nil = icmp eq %runtime._panic* %p, null
+	br i1 %is_nil, label %ret_nil, label %check_recovered
+
+check_recovered:
+	%recovered_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 3
+	%recovered = load %bool, %bool* %recovered_ptr
+	br i1 %recovered, label %ret_nil, label %recover
+
+recover:
+	store %bool true, %bool* %recovered_ptr
+	%tab_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 1
+	%tab = load %runtime.itab*, %runtime.itab** %tab_ptr
+	%data_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 2
+	%data = load i8*, i8** %data_ptr
+	%v_0 = insertvalue { %runtime.itab*, i8* } zeroinitializer, %runtime.itab* %tab, 0
+	%v = insertvalue { %runtime.itab*, i8* } %v_0, i8* %data, 1
+	ret { %runtime.itab*, i8* } %v
+
+ret_nil:
+	ret { %runtime.itab*, i8* } zeroinitializer
+}
+
+; Runtime type descriptor of runtime errors, as created by runtime.panicerror.
+;
+;    type plainError string
+;
+;    func (e plainError) Error() string
+;    func (e plainError) RuntimeError()
+@"type:runtime.plainError" = private constant %runtime._type { %string { i8* getelementptr ([18 x i8], [18 x i8]* @"runtime.str.plainError.data", i64 0, i64 0), %int 18 }, %int 16, %uint8 24, i1 (i8*, i8*)* @runtime.plainError.equal, %uint64 (i8*, %uint64)* @runtime.strhash, %runtime.method* getelementptr ([2 x %runtime.method], [2 x %runtime.method]* @"type:runtime.plainError$methods", i64 0, i64 0), %int 2 }
+@"type:runtime.plainError$methods" = private constant [2 x %runtime.method] [
+	%runtime.method { %string { i8* getelementptr ([19 x i8], [19 x i8]* @"runtime.str.error_key.data", i64 0, i64 0), %int 19 }, i8* bitcast (%string (i8*)* @runtime.plainError.Error to i8*) },
+	%runtime.method { %string { i8* getelementptr ([19 x i8], [19 x i8]* @"runtime.str.runtime_error_key.data", i64 0, i64 0), %int 19 }, i8* bitcast (void (i8*)* @runtime.plainError.RuntimeError to i8*) }
+]
+
+; Interface method table of runtime errors held by empty interface values. The
+; interface type is left nil, as it is not used for empty interfaces.
+@"itab:runtime.plainError,interface {}" = private constant %runtime.itab { %runtime._type* null, %runtime._type* @"type:runtime.plainError", [0 x i8*] zeroinitializer }
+
+; func (e runtime.plainError) Error() string
+;
+;    Error returns the error message of the runtime error e (passed by data
+;    pointer).
+define %string @runtime.plainError.Error(i8* %e) {
+entry:
+	%ptr = bitcast i8* %e to %string*
+	%msg = load %string, %string* %ptr
+	ret %string %msg
+}
+
+; func (e runtime.plainError) RuntimeError()
+;
+;    RuntimeError marks e as a runtime error.
+define void @runtime.plainError.RuntimeError(i8* %e) {
+entry:
+	ret void
+}
+
+; func runtime.plainError.equal(x, y unsafe.Pointer) bool
+;
+;    equal reports whether the runtime errors pointed to by x and y are equal.
+define i1 @runtime.plainError.equal(i8* %x, i8* %y) {
+entry:
+	%x_ptr = bitcast i8* %x to %string*
+	%x_msg = load %string, %string* %x_ptr
+	%y_ptr = bitcast i8* %y to %string*
+	%y_msg = load %string, %string* %y_ptr
+	%result = call %bool @runtime.strequal(%string %x_msg, %string %y_msg)
+	ret i1 %result
+}
+
+; func runtime.panicerror(msg string)
+;
+;    panicerror panics with a runtime error with the given error message.
+define void @runtime.panicerror(%string %msg) {
+entry:
+	%mem = call i8* @calloc(i64 1, i64 16)
+	%ptr = bitcast i8* %mem to %string*
+	store %string %msg, %string* %ptr
+	call void @runtime.gopanic(%runtime.itab* @"itab:runtime.plainError,interface {}", i8* %mem)
+	unreachable
+}
+
+; func runtime.fatalpanic(msgs *_panic)
+;
+;    fatalpanic terminates a panicking program, after the pending deferred calls
+;    have been run. The messages of the active panics msgs are written to
+;    standard error, and the program exits with status code 2. As done by gc,
+;    panics with the same panic value as the panic they interrupted (e.g. by
+;    re-panicking with a recovered value) are marked as repanicked, and printed
+;    only once.
+;
+;       panic: something bad happened
+;
+;       goroutine 1 [running]:
+define void @runtime.fatalpanic(%runtime._panic* %msgs) {
+entry:
+	br label %loop
+
+loop:
+	%p = phi %runtime._panic* [ %msgs, %entry ], [ %link, %next ]
+	%link_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 0
+	%link = load %runtime._panic*, %runtime._panic** %link_ptr
+	%has_link = icmp ne %runtime._panic* %link, null
+	br i1 %has_link, label %check, label %print
+
+check:
+	%tab_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 1
+	%tab = load %runtime.itab*, %runtime.itab** %tab_ptr
+	%link_tab_ptr = getelementptr %runtime._panic, %runtime._panic* %link, i64 0, i32 1
+	%link_tab = load %runtime.itab*, %runtime.itab** %link_tab_ptr
+	%type_ptr = getelementptr %runtime.itab, %runtime.itab* %tab, i64 0, i32 1
+	%type = load %runtime._type*, %runtime._type** %type_ptr
+	%link_type_ptr = getelementptr %runtime.itab, %runtime.itab* %link_tab, i64 0, i32 1
+	%link_type = load %runtime._type*, %runtime._type** %link_type_ptr
+	%same_type = icmp eq %runtime._type* %type, %link_type
+	%data_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 2
+	%data = load i8*, i8** %data_ptr
+	%link_data_ptr = getelementptr %runtime._panic, %runtime._panic* %link, i64 0, i32 2
+	%link_data = load i8*, i8** %link_data_ptr
+	%same_data = icmp eq i8* %data, %link_data
+	%same = and i1 %same_type, %same_data
+	br i1 %same, label %mark, label %next
+
+mark:
+	%repanicked_ptr = getelementptr %runtime._panic, %runtime._panic* %link, i64 0, i32 4
+	store %bool true, %bool* %repanicked_ptr
+	br label %next
+
+next:
+	br label %loop
+
+print:
+	call void @runtime.printpanics(%runtime._panic* %msgs)
+	%goroutine = load %string, %string* @"runtime.str.goroutine"
+	call void @runtime.printstring(%string %goroutine)
+	call void @exit(i32 2)
+	unreachable
+}
+
+; func runtime.printpanics(p *_panic)
+;
+;    printpanics writes the message of the panic p, preceded by the messages of
+;    the panics interrupted by p, to standard error. Recovered panics are marked
+;    as such.
+;
+;       panic: first [recovered]
+;       	panic: second
+define void @runtime.printpanics(%runtime._panic* %p) {
+entry:
+	%link_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 0
+	%link = load %runtime._panic*, %runtime._panic** %link_ptr
+	%has_link = icmp ne %runtime._panic* %link, null
+	br i1 %has_link, label %print_link, label %print_panic
+
+print_link:
+	call void @runtime.printpanics(%runtime._panic* %link)
+	%link_repanicked_ptr = getelementptr %runtime._panic, %runtime._panic* %link, i64 0, i32 4
+	%link_repanicked = load %bool, %bool* %link_repanicked_ptr
+	br i1 %link_repanicked, label %exit, label %print_indent
+
+print_indent:
+	%indent = load %string, %string* @"runtime.str.tab"
+	call void @runtime.printstring(%string %indent)
+	br label %print_panic
+
+print_panic:
+	%panic = load %string, %string* @"runtime.str.panic"
+	call void @runtime.printstring(%string %panic)
+	%tab_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 1
+	%tab = load %runtime.itab*, %runtime.itab** %tab_ptr
+	%data_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 2
+	%data = load i8*, i8** %data_ptr
+	call void @runtime.printpanicval(%runtime.itab* %tab, i8* %data)
+	%recovered_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 3
+	%recovered = load %bool, %bool* %recovered_ptr
+	br i1 %recovered, label %print_recovered, label %print_newline
+
+print_recovered:
+	%repanicked_ptr = getelementptr %runtime._panic, %runtime._panic* %p, i64 0, i32 4
+	%repanicked = load %bool, %bool* %repanicked_ptr
+	%recovered_repanicked = load %string, %string* @"runtime.str.recovered_repanicked"
+	%recovered_only = load %string, %string* @"runtime.str.recovered"
+	%suffix = select i1 %repanicked, %string %recovered_repanicked, %string %recovered_only
+	call void @runtime.printstring(%string %suffix)
+	br label %print_newline
+
+print_newline:
+	%newline = load %string, %string* @"runtime.str.newline"
+	call void @runtime.printstring(%string %newline)
+	br label %exit
+
+exit:
+	ret void
+}
+
+; func runtime.printpanicval(tab *itab, data unsafe.Pointer)
+;
+;    printpanicval writes the panic value with interface method table tab and
+;    data pointer data to standard error. As done by gc, errors and Stringers are
+;    printed using their Error and String methods respectively.
+define void @runtime.printpanicval(%runtime.itab* %tab, i8* %data) {
+entry:
+	%is_nil = icmp eq %runtime.itab* %tab, null
+	br i1 %is_nil, label %print_nil, label %check_error
+
+print_nil:
+	%nil = load %string, %string* @"runtime.str.nil"
+	call void @runtime.printstring(%string %nil)
+	ret void
+
+check_error:
+	%typ_ptr = getelementptr %runtime.itab, %runtime.itab* %tab, i64 0, i32 1
+	%typ = load %runtime._type*, %runtime._type** %typ_ptr
+	%error_key = load %string, %string* @"runtime.str.error_key"
+	%error = call i8* @runtime.findmethod(%runtime._type* %typ, %string %error_key)
+	%is_error = icmp ne i8* %error, null
+	br i1 %is_error, label %print_method, label %check_stringer
+
+check_stringer:
+	%string_key = load %string, %string* @"runtime.str.string_key"
+	%stringer = call i8* @runtime.findmethod(%runtime._type* %typ, %string %string_key)
+	%is_stringer = icmp ne i8* %stringer, null
+	br i1 %is_stringer, label %print_method, label %print_value
+
+	; The receiver of methods in runtime type descriptors is passed as data
+	; pointer.
+print_method:
+	%method = phi i8* [ %error, %check_error ], [ %stringer, %check_stringer ]
+	%method_fn = bitcast i8* %method to %string (i8*)*
+	%s = call %string %method_fn(i8* %data)
+	call void @runtime.printindented(%string %s)
+	ret void
+
+print_value:
+	call void @runtime.printanyvalue(%runtime._type* %typ, i8* %data)
+	ret void
+}
+
+; func runtime.printanyvalue(t *_type, data unsafe.Pointer)
+;
+;    printanyvalue writes the value of type t held by an interface value with
+;    the given data pointer to standard error. Values of predeclared boolean,
+;    numeric and string types are printed as is, values of named types with such
+;    underlying types are qualified by type name, and values of other types are
+;    printed by type name and data pointer.
+;
+;       panic: 5
+;       panic: main.T(5)
+;       panic: main.S("foo")
+;       panic: (*main.T) 0xc000012120
+define void @runtime.printanyvalue(%runtime._type* %t, i8* %data) {
+entry:
+	%data_addr = alloca i8*
+	store i8* %data, i8** %data_addr
+	%ptr = call i8* @runtime.ifacedataptr(%runtime._type* %t, i8** %data_addr)
+	%kind_ptr = getelementptr %runtime._type, %runtime._type* %t, i64 0, i32 2
+	%kind_flags = load %uint8, %uint8* %kind_ptr
+	%kind = and %uint8 %kind_flags, 31 ; kindMask
+	%is_basic = icmp ule %uint8 %kind, 16 ; kindComplex128
+	%is_string = icmp eq %uint8 %kind, 24 ; kindString
+	%is_value = or i1 %is_basic, %is_string
+	br i1 %is_value, label %check_named, label %print_pointer
+
+print_pointer:
+	%lparen = load %string, %string* @"runtime.str.lparen"
+	call void @runtime.printstring(%string %lparen)
+	call void @runtime.printtypename(%runtime._type* %t)
+	%rparen_space = load %string, %string* @"runtime.str.rparen_space"
+	call void @runtime.printstring(%string %rparen_space)
+	%addr = ptrtoint i8* %data to %uint64
+	call void @runtime.printhex(%uint64 %addr)
+	ret void
+
+	; Type names of named types are qualified by package name; complex values
+	; are already parenthesized.
+check_named:
+	%name_ptr = getelementptr %runtime._type, %runtime._type* %t, i64 0, i32 0
+	%name = load %string, %string* %name_ptr
+	%dot = call %int @runtime.indexbyte(%string %name, i8 46) ; '.'
+	%named = icmp sge %int %dot, 0
+	%is_complex64 = icmp eq %uint8 %kind, 15 ; kindComplex64
+	%is_complex128 = icmp eq %uint8 %kind, 16 ; kindComplex128
+	%is_complex = or i1 %is_complex64, %is_complex128
+	%not_complex = xor i1 %is_complex, true
+	%paren = and i1 %named, %not_complex
+	br i1 %named, label %print_name, label %print_value
+
+print_name:
+	call void @runtime.printtypename(%runtime._type* %t)
+	br i1 %paren, label %print_lparen, label %print_value
+
+print_lparen:
+	%lparen_plain = load %string, %string* @"runtime.str.lparen"
+	%lparen_quote = load %string, %string* @"runtime.str.lparen_quote"
+	%lparen_value = select i1 %is_string, %string %lparen_quote, %string %lparen_plain
+	call void @runtime.printstring(%string %lparen_value)
+	br label %print_value
+
+print_value:
+	switch %uint8 %kind, label %print_string [
+		%uint8 1, label %print_bool
+		%uint8 2, label %load_int
+		%uint8 3, label %load_int8
+		%uint8 4, label %load_int16
+		%uint8 5, label %load_int32
+		%uint8 6, label %load_int64
+		%uint8 7, label %load_uint
+		%uint8 8, label %load_uint8
+		%uint8 9, label %load_uint16
+		%uint8 10, label %load_uint32
+		%uint8 11, label %load_uint64
+		%uint8 12, label %load_uintptr
+		%uint8 13, label %print_float32
+		%uint8 14, label %print_float64
+		%uint8 15, label %print_complex64
+		%uint8 16, label %print_complex128
+	]
+
+print_bool:
+	%bool_ptr = bitcast i8* %ptr to %bool*
+	%bool = load %bool, %bool* %bool_ptr
+	%true = load %string, %string* @"runtime.str.true"
+	%false = load %string, %string* @"runtime.str.false"
+	%bool_str = select i1 %bool, %string %true, %string %false
+	call void @runtime.printstring(%string %bool_str)
+	br label %print_suffix
+
+load_int:
+	%int_ptr = bitcast i8* %ptr to %int*
+	%int = load %int, %int* %int_ptr
+	br label %print_signed
+
+load_int8:
+	%int8_ptr = bitcast i8* %ptr to %int8*
+	%int8 = load %int8, %int8* %int8_ptr
+	%int8_ext = sext %int8 %int8 to %int64
+	br label %print_signed
+
+load_int16:
+	%int16_ptr = bitcast i8* %ptr to %int16*
+	%int16 = load %int16, %int16* %int16_ptr
+	%int16_ext = sext %int16 %int16 to %int64
+	br label %print_signed
+
+load_int32:
+	%int32_ptr = bitcast i8* %ptr to %int32*
+	%int32 = load %int32, %int32* %int32_ptr
+	%int32_ext = sext %int32 %int32 to %int64
+	br label %print_signed
+
+load_int64:
+	%int64_ptr = bitcast i8* %ptr to %int64*
+	%int64 = load %int64, %int64* %int64_ptr
+	br label %print_signed
+
+print_signed:
+	%signed = phi %int64 [ %int, %load_int ], [ %int8_ext, %load_int8 ], [ %int16_ext, %load_int16 ], [ %int32_ext, %load_int32 ], [ %int64, %load_int64 ]
+	call void @runtime.printint(%int64 %signed)
+	br label %print_suffix
+
+load_uint:
+	%uint_ptr = bitcast i8* %ptr to %uint*
+	%uint = load %uint, %uint* %uint_ptr
+	br label %print_unsigned
+
+load_uint8:
+	%uint8_ptr = bitcast i8* %ptr to %uint8*
+	%uint8 = load %uint8, %uint8* %uint8_ptr
+	%uint8_ext = zext %uint8 %uint8 to %uint64
+	br label %print_unsigned
+
+load_uint16:
+	%uint16_ptr = bitcast i8* %ptr to %uint16*
+	%uint16 = load %uint16, %uint16* %uint16_ptr
+	%uint16_ext = zext %uint16 %uint16 to %uint64
+	br label %print_unsigned
+
+load_uint32:
+	%uint32_ptr = bitcast i8* %ptr to %uint32*
+	%uint32 = load %uint32, %uint32* %uint32_ptr
+	%uint32_ext = zext %uint32 %uint32 to %uint64
+	br label %print_unsigned
+
+load_uint64:
+	%uint64_ptr = bitcast i8* %ptr to %uint64*
+	%uint64 = load %uint64, %uint64* %uint64_ptr
+	br label %print_unsigned
+
+load_uintptr:
+	%uintptr_ptr = bitcast i8* %ptr to %uintptr*
+	%uintptr = load %uintptr, %uintptr* %uintptr_ptr
+	br label %print_unsigned
+
+print_unsigned:
+	%unsigned = phi %uint64 [ %uint, %load_uint ], [ %uint8_ext, %load_uint8 ], [ %uint16_ext, %load_uint16 ], [ %uint32_ext, %load_uint32 ], [ %uint64, %load_uint64 ], [ %uintptr, %load_uintptr ]
+	call void @runtime.printuint(%uint64 %unsigned)
+	br label %print_suffix
+
+print_float32:
+	%float32_ptr = bitcast i8* %ptr to %float32*
+	%float32 = load %float32, %float32* %float32_ptr
+	%float32_ext = fpext %float32 %float32 to double
+	call void @runtime.printfloat(double %float32_ext, i1 true)
+	br label %print_suffix
+
+print_float64:
+	%float64_ptr = bitcast i8* %ptr to %float64*
+	%float64 = load %float64, %float64* %float64_ptr
+	call void @runtime.printfloat(double %float64, i1 false)
+	br label %print_suffix
+
+print_complex64:
+	%complex64_ptr = bitcast i8* %ptr to %complex64*
+	%complex64 = load %complex64, %complex64* %complex64_ptr
+	%real64 = extractvalue %complex64 %complex64, 0
+	%real64_ext = fpext %float32 %real64 to double
+	%imag64 = extractvalue %complex64 %complex64, 1
+	%imag64_ext = fpext %float32 %imag64 to double
+	call void @runtime.printcomplex(double %real64_ext, double %imag64_ext, i1 true)
+	br label %print_suffix
+
+print_complex128:
+	%complex128_ptr = bitcast i8* %ptr to %complex128*
+	%complex128 = load %complex128, %complex128* %complex128_ptr
+	%real128 = extractvalue %complex128 %complex128, 0
+	%imag128 = extractvalue %complex128 %complex128, 1
+	call void @runtime.printcomplex(double %real128, double %imag128, i1 false)
+	br label %print_suffix
+
+print_string:
+	%string_ptr = bitcast i8* %ptr to %string*
+	%string = load %string, %string* %string_ptr
+	call void @runtime.printindented(%string %string)
+	br label %print_suffix
+
+print_suffix:
+	br i1 %paren, label %print_rparen, label %exit
+
+print_rparen:
+	%rparen_plain = load %string, %string* @"runtime.str.rparen"
+	%rparen_quote = load %string, %string* @"runtime.str.quote_rparen"
+	%rparen_value = select i1 %is_string, %string %rparen_quote, %string %rparen_plain
+	call void @runtime.printstring(%string %rparen_value)
+	br label %exit
+
+exit:
+	ret void
+}
+
+; func runtime.findmethod(t *_type, key string) unsafe.Pointer
+;
+;    findmethod returns the function pointer of the method of t with the given
+;    method key ("name signature"), or nil if t has no such method.
+define i8* @runtime.findmethod(%runtime._type* %t, %string %key) {
+entry:
+	%n_ptr = getelementptr %runtime._type, %runtime._type* %t, i64 0, i32 6
+	%n = load %int, %int* %n_ptr
+	%methods_ptr = getelementptr %runtime._type, %runtime._type* %t, i64 0, i32 5
+	%methods = load %runtime.method*, %runtime.method** %methods_ptr
+	br label %loop.cond
+
+loop.cond:
+	%i = phi %int [ 0, %entry ], [ %i.inc, %loop.post ]
+	%cond = icmp slt %int %i, %n
+	br i1 %cond, label %loop.body, label %ret_nil
+
+loop.body:
+	%name_ptr = getelementptr %runtime.method, %runtime.method* %methods, %int %i, i32 0
+	%name = load %string, %string* %name_ptr
+	%found = call %bool @runtime.strequal(%string %name, %string %key)
+	br i1 %found, label %ret_method, label %loop.post
+
+ret_method:
+	%fn_ptr = getelementptr %runtime.method, %runtime.method* %methods, %int %i, i32 1
+	%fn = load i8*, i8** %fn_ptr
+	ret i8* %fn
+
+loop.post:
+	%i.inc = add %int %i, 1
+	br label %loop.cond
+
+ret_nil:
+	ret i8* null
+}
+
+; func runtime.printstring(s string)
+;
+;    printstring writes s to standard error.
+define void @runtime.printstring(%string %s) {
+entry:
+	%data = extractvalue %string %s, 0
+	%len = extractvalue %string %s, 1
+	call i64 @write(i64 2, i8* %data, i64 %len)
+	ret void
+}
+
+; func runtime.printindented(s string)
+;
+;    printindented writes s to standard error, indenting each line but the first
+;    by a tab.
+define void @runtime.printindented(%string %s) {
+entry:
+	%data = extractvalue %string %s, 0
+	%len = extractvalue %string %s, 1
+	br label %loop.cond
+
+loop.cond:
+	%i = phi %int [ 0, %entry ], [ %i.inc, %loop.post ]
+	%start = phi %int [ 0, %entry ], [ %next, %loop.post ]
+	%cond = icmp slt %int %i, %len
+	br i1 %cond, label %loop.body, label %loop.exit
+
+loop.body:
+	%p = getelementptr %uint8, %uint8* %data, %int %i
+	%c = load %uint8, %uint8* %p
+	%i.inc = add %int %i, 1
+	%is_newline = icmp eq %uint8 %c, 10 ; '\n'
+	br i1 %is_newline, label %print_line, label %loop.post
+
+print_line:
+	%line = getelementptr %uint8, %uint8* %data, %int %start
+	%line_len = sub %int %i.inc, %start
+	call i64 @write(i64 2, i8* %line, i64 %line_len)
+	%indent = load %string, %string* @"runtime.str.tab"
+	call void @runtime.printstring(%string %indent)
+	br label %loop.post
+
+loop.post:
+	%next = phi %int [ %start, %loop.body ], [ %i.inc, %print_line ]
+	br label %loop.cond
+
+loop.exit:
+	%rest = getelementptr %uint8, %uint8* %data, %int %start
+	%rest_len = sub %int %len, %start
+	call i64 @write(i64 2, i8* %rest, i64 %rest_len)
+	ret void
+}
+
+; func runtime.printtypename(t *_type)
+;
+;    printtypename writes the name of the type t to standard error.
+define void @runtime.printtypename(%runtime._type* %t) {
+entry:
+	%name_ptr = getelementptr %runtime._type, %runtime._type* %t, i64 0, i32 0
+	%name = load %string, %string* %name_ptr
+	call void @runtime.printstring(%string %name)
+	ret void
+}
+
+; func runtime.printhex(v uint64)
+;
+;    printhex writes the hexadecimal representation of v, prefixed by "0x", to
+;    standard error.
+define void @runtime.printhex(%uint64 %v) {
+entry:
+	%prefix = load %string, %string* @"runtime.str.0x"
+	call void @runtime.printstring(%string %prefix)
+	%buf = alloca [16 x i8]
+	br label %loop
+
+loop:
+	%i = phi %int [ 16, %entry ], [ %i.dec, %loop ]
+	%x = phi %uint64 [ %v, %entry ], [ %x.shr, %loop ]
+	%i.dec = sub %int %i, 1
+	%digit = and %uint64 %x, 15
+	%digit8 = trunc %uint64 %digit to i8
+	%is_decimal = icmp ult i8 %digit8, 10
+	%decimal = add i8 %digit8, 48 ; '0'
+	%letter = add i8 %digit8, 87 ; 'a' - 10
+	%c = select i1 %is_decimal, i8 %decimal, i8 %letter
+	%p = getelementptr [16 x i8], [16 x i8]* %buf, i64 0, %int %i.dec
+	store i8 %c, i8* %p
+	%x.shr = lshr %uint64 %x, 4
+	%more = icmp ne %uint64 %x.shr, 0
+	br i1 %more, label %loop, label %done
+
+done:
+	%n = sub %int 16, %i.dec
+	call i64 @write(i64 2, i8* %p, i64 %n)
+	ret void
+}
+
+; func runtime.printfloat(v float64, is32 bool)
+;
+;    printfloat writes the shortest decimal representation of v (of type float32
+;    if is32 is set) to standard error.
+define void @runtime.printfloat(double %v, i1 %is32) {
+entry:
+	%buf = alloca [32 x i8]
+	%p = getelementptr [32 x i8], [32 x i8]* %buf, i64 0, i64 0
+	%s = call %string @runtime.formatfloat(i8* %p, double %v, i1 %is32)
+	call void @runtime.printstring(%string %s)
+	ret void
+}
+
+; func runtime.printcomplex(real, imag float64, is32 bool)
+;
+;    printcomplex writes the decimal representation of the complex value with
+;    the given real and imaginary parts (of type float32 if is32 is set) to
+;    standard error.
+;
+;       (1.5-2i)
+define void @runtime.printcomplex(double %real, double %imag, i1 %is32) {
+entry:
+	%buf = alloca [32 x i8]
+	%p = getelementptr [32 x i8], [32 x i8]* %buf, i64 0, i64 0
+	%lparen = load %string, %string* @"runtime.str.lparen"
+	call void @runtime.printstring(%string %lparen)
+	call void @runtime.printfloat(double %real, i1 %is32)
+	; The imaginary part is always signed.
+	%imag_str = call %string @runtime.formatfloat(i8* %p, double %imag, i1 %is32)
+	%imag_data = extractvalue %string %imag_str, 0
+	%c = load i8, i8* %imag_data
+	%is_plus = icmp eq i8 %c, 43 ; '+'
+	%is_minus = icmp eq i8 %c, 45 ; '-'
+	%signed = or i1 %is_plus, %is_minus
+	br i1 %signed, label %print_imag, label %print_plus
+
+print_plus:
+	%plus = load %string, %string* @"runtime.str.plus"
+	call void @runtime.printstring(%string %plus)
+	br label %print_imag
+
+print_imag:
+	call void @runtime.printstring(%string %imag_str)
+	%imag_suffix = load %string, %string* @"runtime.str.imag_rparen"
+	call void @runtime.printstring(%string %imag_suffix)
+	ret void
+}
+
+; int snprintf(char *str, size_t size, const char *format, ...)
+declare i32 @snprintf(i8* %str, i64 %size, i8* %format, ...)
+
+; double strtod(const char *nptr, char **endptr)
+declare double @strtod(i8* %nptr, i8** %endptr)
+
+; float strtof(const char *nptr, char **endptr)
+declare float @strtof(i8* %nptr, i8** %endptr)
+
+; char *strchr(const char *s, int c)
+declare i8* @strchr(i8* %s, i32 %c)
+
+; int atoi(const char *nptr)
+declare i32 @atoi(i8* %nptr)
+
+@"runtime.fmt.e" = private unnamed_addr constant [5 x i8] c"%.*e\00"
+@"runtime.fmt.f" = private unnamed_addr constant [5 x i8] c"%.*f\00"
+
+; func runtime.formatfloat(buf *[32]byte, v float64, is32 bool) string
+;
+;    formatfloat returns the shortest decimal representation of v (of type
+;    float32 if is32 is set) that converts back to v, using the format of
+;    strconv.FormatFloat(v, 'g', -1, bitSize) as done by gc; the exponent form is
+;    used for exponents less than -4 or greater than or equal to 6. The result is
+;    stored in buf unless v is NaN or infinite.
+;
+;       1.5
+;       1e+06
+;       -2.5e-05
+define %string @runtime.formatfloat(i8* %buf, double %v, i1 %is32) {
+entry:
+	%is_nan = fcmp uno double %v, %v
+	br i1 %is_nan, label %ret_nan, label %check_inf
+
+ret_nan:
+	%nan = load %string, %string* @"runtime.str.nan"
+	ret %string %nan
+
+check_inf:
+	%abs = call double @llvm.fabs.f64(double %v)
+	%is_inf = fcmp oeq double %abs, 0x7FF0000000000000
+	br i1 %is_inf, label %ret_inf, label %shortest.cond
+
+ret_inf:
+	%is_neg = fcmp olt double %v, 0.0
+	%pos_inf = load %string, %string* @"runtime.str.pos_inf"
+	%neg_inf = load %string, %string* @"runtime.str.neg_inf"
+	%inf = select i1 %is_neg, %string %neg_inf, %string %pos_inf
+	ret %string %inf
+
+	; Find the shortest precision (digits after the decimal point of the
+	; exponent form) for which the decimal representation converts back to v.
+shortest.cond:
+	%prec = phi i32 [ 0, %check_inf ], [ %prec.inc, %shortest.post ]
+	%fmt_e = getelementptr [5 x i8], [5 x i8]* @"runtime.fmt.e", i64 0, i64 0
+	%n_e = call i32 (i8*, i64, i8*, ...) @snprintf(i8* %buf, i64 32, i8* %fmt_e, i32 %prec, double %v)
+	br i1 %is32, label %shortest.float32, label %shortest.float64
+
+shortest.float32:
+	%v32 = fptrunc double %v to float
+	%r32 = call float @strtof(i8* %buf, i8** null)
+	%eq32 = fcmp oeq float %r32, %v32
+	br label %shortest.check
+
+shortest.float64:
+	%r64 = call double @strtod(i8* %buf, i8** null)
+	%eq64 = fcmp oeq double %r64, %v
+	br label %shortest.check
+
+shortest.check:
+	%eq = phi i1 [ %eq32, %shortest.float32 ], [ %eq64, %shortest.float64 ]
+	%max_prec = icmp sge i32 %prec, 16
+	%done = or i1 %eq, %max_prec
+	br i1 %done, label %shortest.done, label %shortest.post
+
+shortest.post:
+	%prec.inc = add i32 %prec, 1
+	br label %shortest.cond
+
+shortest.done:
+	%e_ptr = call i8* @strchr(i8* %buf, i32 101) ; 'e'
+	%exp_ptr = getelementptr i8, i8* %e_ptr, i64 1
+	%exp = call i32 @atoi(i8* %exp_ptr)
+	%exp_small = icmp slt i32 %exp, -4
+	%exp_large = icmp sge i32 %exp, 6
+	%use_e = or i1 %exp_small, %exp_large
+	br i1 %use_e, label %ret_e, label %ret_f
+
+ret_e:
+	%len_e = sext i32 %n_e to %int
+	%s_e.0 = insertvalue %string zeroinitializer, i8* %buf, 0
+	%s_e = insertvalue %string %s_e.0, %int %len_e, 1
+	ret %string %s_e
+
+	; The same digits in fixed-point form; max(prec-exp, 0) digits after the
+	; decimal point.
+ret_f:
+	%fmt_f = getelementptr [5 x i8], [5 x i8]* @"runtime.fmt.f", i64 0, i64 0
+	%frac = sub i32 %prec, %exp
+	%frac_neg = icmp slt i32 %frac, 0
+	%frac_prec = select i1 %frac_neg, i32 0, i32 %frac
+	%n_f = call i32 (i8*, i64, i8*, ...) @snprintf(i8* %buf, i64 32, i8* %fmt_f, i32 %frac_prec, double %v)
+	%len_f = sext i32 %n_f to %int
+	%s_f.0 = insertvalue %string zeroinitializer, i8* %buf, 0
+	%s_f = insertvalue %string %s_f.0, %int %len_f, 1
+	ret %string %s_f
+}
+
+; func runtime.panicdottype(have, want, iface *_type)
+;
+;    panicdottype panics on a failed type assertion of an interface value
+;    (of static type iface, or nil for unknown interface type) holding the
+;    dynamic type have (or nil for nil interface values) to the type want.
+;
+;       panic: interface conversion: interface {} is string, not int
+define void @runtime.panicdottype(%runtime._type* %have, %runtime._type* %want, %runtime._type* %iface) {
+entry:
+	%iface_nil = icmp eq %runtime._type* %iface, null
+	br i1 %iface_nil, label %iface_interface, label %iface_name
+
+iface_interface:
+	%interface = load %string, %string* @"runtime.str.interface"
+	br label %check_have
+
+iface_name:
+	%iface_name_ptr = getelementptr %runtime._type, %runtime._type* %iface, i64 0, i32 0
+	%iface_type_name = load %string, %string* %iface_name_ptr
+	br label %check_have
+
+check_have:
+	%iface_str = phi %string [ %interface, %iface_interface ], [ %iface_type_name, %iface_name ]
+	%have_nil = icmp eq %runtime._type* %have, null
+	br i1 %have_nil, label %have_nil_str, label %have_name
+
+have_nil_str:
+	%nil = load %string, %string* @"runtime.str.nil"
+	br label %panic
+
+have_name:
+	%have_name_ptr = getelementptr %runtime._type, %runtime._type* %have, i64 0, i32 0
+	%have_type_name = load %string, %string* %have_name_ptr
+	br label %panic
+
+panic:
+	%have_str = phi %string [ %nil, %have_nil_str ], [ %have_type_name, %have_name ]
+	%want_name_ptr = getelementptr %runtime._type, %runtime._type* %want, i64 0, i32 0
+	%want_str = load %string, %string* %want_name_ptr
+	%iface_conv = load %string, %string* @"runtime.str.iface_conv"
+	%is = load %string, %string* @"runtime.str.is"
+	%not = load %string, %string* @"runtime.str.not"
+	%msg.0 = call %string @runtime.concatstring2(%string %iface_conv, %string %iface_str)
+	%msg.1 = call %string @runtime.concatstring2(%string %msg.0, %string %is)
+	%msg.2 = call %string @runtime.concatstring2(%string %msg.1, %string %have_str)
+	%msg.3 = call %string @runtime.concatstring2(%string %msg.2, %string %not)
+	%msg = call %string @runtime.concatstring2(%string %msg.3, %string %want_str)
+	call void @runtime.panicerror(%string %msg)
+	unreachable
+}
+
+; func runtime.panicmissingmethod(concrete, inter *_type, method string)
+;
+;    panicmissingmethod panics on a failed type assertion of an interface value
+;    holding the dynamic type concrete to the interface type inter, which
+;    requires the given method (in method key form, "name signature").
+;
+;       panic: interface conversion: main.T is not main.I: missing method M
+define void @runtime.panicmissingmethod(%runtime._type* %concrete, %runtime._type* %inter, %string %method) {
+entry:
+	; method name; i.e. the method key up to the first space.
+	%space = call %int @runtime.indexbyte(%string %method, i8 32) ; ' '
+	%method_name = insertvalue %string %method, %int %space, 1
+	%concrete_name_ptr = getelementptr %runtime._type, %runtime._type* %concrete, i64 0, i32 0
+	%concrete_name = load %string, %string* %concrete_name_ptr
+	%inter_name_ptr = getelementptr %runtime._type, %runtime._type* %inter, i64 0, i32 0
+	%inter_name = load %string, %string* %inter_name_ptr
+	%iface_conv = load %string, %string* @"runtime.str.iface_conv"
+	%is_not = load %string, %string* @"runtime.str.is_not"
+	%missing_method = load %string, %string* @"runtime.str.missing_method"
+	%msg.0 = call %string @runtime.concatstring2(%string %iface_conv, %string %concrete_name)
+	%msg.1 = call %string @runtime.concatstring2(%string %msg.0, %string %is_not)
+	%msg.2 = call %string @runtime.concatstring2(%string %msg.1, %string %inter_name)
+	%msg.3 = call %string @runtime.concatstring2(%string %msg.2, %string %missing_method)
+	%msg = call %string @runtime.concatstring2(%string %msg.3, %string %method_name)
+	call void @runtime.panicerror(%string %msg)
+	unreachable
+}
+
+; func runtime.panicuncomparable(t *_type)
+;
+;    panicuncomparable panics on a comparison of interface values holding the
+;    uncomparable type t.
+;
+;       panic: runtime error: comparing uncomparable type []int
+define void @runtime.panicuncomparable(%runtime._type* %t) {
+entry:
+	%name_ptr = getelementptr %runtime._type, %runtime._type* %t, i64 0, i32 0
+	%name = load %string, %string* %name_ptr
+	%runtime_error = load %string, %string* @"runtime.str.runtime_error"
+	%uncomparable = load %string, %string* @"runtime.str.uncomparable"
+	%msg.0 = call %string @runtime.concatstring2(%string %runtime_error, %string %uncomparable)
+	%msg = call %string @runtime.concatstring2(%string %msg.0, %string %name)
+	call void @runtime.panicerror(%string %msg)
+	unreachable
+}
+
+; func runtime.panicunhashable(t *_type)
+;
+;    panicunhashable panics on hashing an interface value holding the unhashable
+;    type t (e.g. when used as map key).
+;
+;       panic: runtime error: hash of unhashable type []int
+define void @runtime.panicunhashable(%runtime._type* %t) {
+entry:
+	%name_ptr = getelementptr %runtime._type, %runtime._type* %t, i64 0, i32 0
+	%name = load %string, %string* %name_ptr
+	%runtime_error = load %string, %string* @"runtime.str.runtime_error"
+	%unhashable = load %string, %string* @"runtime.str.unhashable"
+	%msg.0 = call %string @runtime.concatstring2(%string %runtime_error, %string %unhashable)
+	%msg = call %string @runtime.concatstring2(%string %msg.0, %string %name)
+	call void @runtime.panicerror(%string %msg)
+	unreachable
+}
+
+; === [ type assertions ] ======================================================
+
+; func runtime.typeassert(tab *itab, want, iface *_type, canfail bool) bool
+;
+;    typeassert reports whether the interface value with interface method table
+;    tab (of static type iface) holds the concrete type want. If canfail is
+;    false, a failed type assertion panics.
+define i1 @runtime.typeassert(%runtime.itab* %tab, %runtime._type* %want, %runtime._type* %iface, i1 %canfail) {
+entry:
+	%is_nil = icmp eq %runtime.itab* %tab, null
+	br i1 %is_nil, label %fail, label %check_type
+
+check_type:
+	%typ_ptr = getelementptr %runtime.itab, %runtime.itab* %tab, i64 0, i32 1
+	%typ = load %runtime._type*, %runtime._type** %typ_ptr
+	%same_type = icmp eq %runtime._type* %typ, %want
+	br i1 %same_type, label %ret_true, label %fail
+
+ret_true:
+	ret i1 true
+
+fail:
+	%have = phi %runtime._type* [ null, %entry ], [ %typ, %check_type ]
+	br i1 %canfail, label %ret_false, label %panic
+
+ret_false:
+	ret i1 false
+
+panic:
+	call void @runtime.panicdottype(%runtime._type* %have, %runtime._type* %want, %runtime._type* %iface)
+	unreachable
+}
+
+; %runtime.itabEntry is an entry of the linked list of interface method tables
+; created at run time.
+%runtime.itabEntry = type { %runtime.itab*, %runtime.itabEntry* }
+
+; Head of linked list of interface method tables created at run time.
+@runtime.itabs = global %runtime.itabEntry* null
+
+; func runtime.getitab(inter *_type, tab *itab, canfail bool) *itab
+;
+;    getitab returns the interface method table of the interface type inter for
+;    the dynamic type of the interface value with interface method table tab. If
+;    the dynamic type does not implement inter (or the interface value is nil),
+;    getitab returns nil if canfail is true, and panics otherwise.
+define %runtime.itab* @runtime.getitab(%runtime._type* %inter, %runtime.itab* %tab, i1 %canfail) {
+entry:
+	%is_nil = icmp eq %runtime.itab* %tab, null
+	br i1 %is_nil, label %nil_iface, label %check_tab
+
+nil_iface:
+	br i1 %canfail, label %ret_nil, label %panic_nil
+
+panic_nil:
+	call void @runtime.panicdottype(%runtime._type* null, %runtime._type* %inter, %runtime._type* null)
+	unreachable
+
+ret_nil:
+	ret %runtime.itab* null
+
+	; Fast path; tab is already an interface method table of inter.
+check_tab:
+	%typ_ptr = getelementptr %runtime.itab, %runtime.itab* %tab, i64 0, i32 1
+	%typ = load %runtime._type*, %runtime._type** %typ_ptr
+	%tab_inter_ptr = getelementptr %runtime.itab, %runtime.itab* %tab, i64 0, i32 0
+	%tab_inter = load %runtime._type*, %runtime._type** %tab_inter_ptr
+	%same_inter = icmp eq %runtime._type* %tab_inter, %inter
+	br i1 %same_inter, label %ret_tab, label %cache.pre
+
+ret_tab:
+	ret %runtime.itab* %tab
+
+	; Look for interface method table created at run time.
+cache.pre:
+	%head = load %runtime.itabEntry*, %runtime.itabEntry** @runtime.itabs
+	br label %cache.cond
+
+cache.cond:
+	%cur = phi %runtime.itabEntry* [ %head, %cache.pre ], [ %next, %cache.post ]
+	%at_end = icmp eq %runtime.itabEntry* %cur, null
+	br i1 %at_end, label %build, label %cache.body
+
+cache.body:
+	%entry_tab_ptr = getelementptr %runtime.itabEntry, %runtime.itabEntry* %cur, i64 0, i32 0
+	%entry_tab = load %runtime.itab*, %runtime.itab** %entry_tab_ptr
+	%entry_inter_ptr = getelementptr %runtime.itab, %runtime.itab* %entry_tab, i64 0, i32 0
+	%entry_inter = load %runtime._type*, %runtime._type** %entry_inter_ptr
+	%entry_typ_ptr = getelementptr %runtime.itab, %runtime.itab* %entry_tab, i64 0, i32 1
+	%entry_typ = load %runtime._type*, %runtime._type** %entry_typ_ptr
+	%match_inter = icmp eq %runtime._type* %entry_inter, %inter
+	%match_typ = icmp eq %runtime._type* %entry_typ, %typ
+	%match = and i1 %match_inter, %match_typ
+	br i1 %match, label %ret_cached, label %cache.post
+
+ret_cached:
+	ret %runtime.itab* %entry_tab
+
+cache.post:
+	%next_ptr = getelementptr %runtime.itabEntry, %runtime.itabEntry* %cur, i64 0, i32 1
+	%next = load %runtime.itabEntry*, %runtime.itabEntry** %next_ptr
+	br label %cache.cond
+
+	; Create interface method table.
+build:
+	%inter_n_ptr = getelementptr %runtime._type, %runtime._type* %inter, i64 0, i32 6
+	%inter_n = load %int, %int* %inter_n_ptr
+	%inter_methods_ptr = getelementptr %runtime._type, %runtime._type* %inter, i64 0, i32 5
+	%inter_methods = load %runtime.method*, %runtime.method** %inter_methods_ptr
+	%typ_n_ptr = getelementptr %runtime._type, %runtime._type* %typ, i64 0, i32 6
+	%typ_n = load %int, %int* %typ_n_ptr
+	%typ_methods_ptr = getelementptr %runtime._type, %runtime._type* %typ, i64 0, i32 5
+	%typ_methods = load %runtime.method*, %runtime.method** %typ_methods_ptr
+	; size = sizeof(inter) + sizeof(type) + inter_n*sizeof(i8*)
+	%funcs_size = mul %int %inter_n, 8
+	%size = add %int %funcs_size, 16
+	%mem = call i8* @calloc(%uint64 1, %uint64 %size)
+	%new_tab = bitcast i8* %mem to %runtime.itab*
+	%new_inter_ptr = getelementptr %runtime.itab, %runtime.itab* %new_tab, i64 0, i32 0
+	store %runtime._type* %inter, %runtime._type** %new_inter_ptr
+	%new_typ_ptr = getelementptr %runtime.itab, %runtime.itab* %new_tab, i64 0, i32 1
+	store %runtime._type* %typ, %runtime._type** %new_typ_ptr
+	br label %outer.cond
+
+	; for i := 0; i < inter_n; i++
+outer.cond:
+	%i = phi %int [ 0, %build ], [ %i.inc, %outer.post ]
+	%i_cond = icmp slt %int %i, %inter_n
+	br i1 %i_cond, label %outer.body, label %insert
+
+outer.body:
+	%inter_method_name_ptr = getelementptr %runtime.method, %runtime.method* %inter_methods, %int %i, i32 0
+	%inter_method_name = load %string, %string* %inter_method_name_ptr
+	br label %inner.cond
+
+	; for j := 0; j < typ_n; j++
+inner.cond:
+	%j = phi %int [ 0, %outer.body ], [ %j.inc, %inner.post ]
+	%j_cond = icmp slt %int %j, %typ_n
+	br i1 %j_cond, label %inner.body, label %missing
+
+inner.body:
+	%typ_method_name_ptr = getelementptr %runtime.method, %runtime.method* %typ_methods, %int %j, i32 0
+	%typ_method_name = load %string, %string* %typ_method_name_ptr
+	%cmp = call %int @cmp.string(%string %inter_method_name, %string %typ_method_name)
+	%found = icmp eq %int %cmp, 0
+	br i1 %found, label %store_func, label %inner.post
+
+inner.post:
+	%j.inc = add %int %j, 1
+	br label %inner.cond
+
+store_func:
+	%typ_method_func_ptr = getelementptr %runtime.method, %runtime.method* %typ_methods, %int %j, i32 1
+	%typ_method_func = load i8*, i8** %typ_method_func_ptr
+	%fun_ptr = getelementptr %runtime.itab, %runtime.itab* %new_tab, i64 0, i32 2, %int %i
+	store i8* %typ_method_func, i8** %fun_ptr
+	br label %outer.post
+
+outer.post:
+	%i.inc = add %int %i, 1
+	br label %outer.cond
+
+missing:
+	br i1 %canfail, label %ret_nil, label %panic_missing
+
+panic_missing:
+	call void @runtime.panicmissingmethod(%runtime._type* %typ, %runtime._type* %inter, %string %inter_method_name)
+	unreachable
+
+	; Insert interface method table at the head of the linked list.
+insert:
+	%node_mem = call i8* @calloc(%uint64 1, %uint64 16)
+	%node = bitcast i8* %node_mem to %runtime.itabEntry*
+	%node_tab_ptr = getelementptr %runtime.itabEntry, %runtime.itabEntry* %node, i64 0, i32 0
+	store %runtime.itab* %new_tab, %runtime.itab** %node_tab_ptr
+	%node_next_ptr = getelementptr %runtime.itabEntry, %runtime.itabEntry* %node, i64 0, i32 1
+	store %runtime.itabEntry* %head, %runtime.itabEntry** %node_next_ptr
+	store %runtime.itabEntry* %node, %runtime.itabEntry** @runtime.itabs
+	ret %runtime.itab* %new_tab
+}
+
+; === [ hashing ] ==============================================================
+
+; time_t time(time_t *tloc)
+declare i64 @time(i8* %tloc)
+
+; pid_t getpid(void)
+declare i32 @getpid()
+
+; State of pseudo-random number generator; seeded on first use.
+@runtime.randstate = global %uint64 0
+
+; func runtime.fastrand() uint64
+;
+;    fastrand returns a pseudo-random number (xorshift64*), seeded by the time
+;    and process ID at first use.
+define %uint64 @runtime.fastrand() {
+entry:
+	%state = load %uint64, %uint64* @runtime.randstate
+	%uninit = icmp eq %uint64 %state, 0
+	br i1 %uninit, label %seed, label %next
+
+seed:
+	%time = call i64 @time(i8* null)
+	%pid = call i32 @getpid()
+	%pid64 = zext i32 %pid to %uint64
+	%pid_shl = shl %uint64 %pid64, 32
+	%mix = xor %uint64 %time, %pid_shl
+	%seed_state = or %uint64 %mix, 1
+	br label %next
+
+next:
+	%x0 = phi %uint64 [ %state, %entry ], [ %seed_state, %seed ]
+	%x0_shr = lshr %uint64 %x0, 12
+	%x1 = xor %uint64 %x0, %x0_shr
+	%x1_shl = shl %uint64 %x1, 25
+	%x2 = xor %uint64 %x1, %x1_shl
+	%x2_shr = lshr %uint64 %x2, 27
+	%x3 = xor %uint64 %x2, %x2_shr
+	store %uint64 %x3, %uint64* @runtime.randstate
+	%result = mul %uint64 %x3, 2685821657736338717
+	ret %uint64 %result
+}
+
+; func runtime.memhash(p unsafe.Pointer, n int, seed uint64) uint64
+;
+;    memhash returns the hash of the n bytes at p (FNV-1a with a final
+;    avalanche step), based on the given seed.
+define %uint64 @runtime.memhash(i8* %p, %int %n, %uint64 %seed) {
+entry:
+	%h0 = xor %uint64 %seed, -3750763034362895579 ; FNV offset basis
+	br label %loop.cond
+
+loop.cond:
+	%i = phi %int [ 0, %entry ], [ %i.inc, %loop.body ]
+	%h = phi %uint64 [ %h0, %entry ], [ %h.next, %loop.body ]
+	%cond = icmp slt %int %i, %n
+	br i1 %cond, label %loop.body, label %loop.exit
+
+loop.body:
+	%bp = getelementptr %uint8, %uint8* %p, %int %i
+	%b = load %uint8, %uint8* %bp
+	%b64 = zext %uint8 %b to %uint64
+	%hx = xor %uint64 %h, %b64
+	%h.next = mul %uint64 %hx, 1099511628211 ; FNV prime
+	%i.inc = add %int %i, 1
+	br label %loop.cond
+
+loop.exit:
+	%h_shr1 = lshr %uint64 %h, 33
+	%f1 = xor %uint64 %h, %h_shr1
+	%f2 = mul %uint64 %f1, -49064778989728563
+	%f2_shr = lshr %uint64 %f2, 33
+	%f3 = xor %uint64 %f2, %f2_shr
+	ret %uint64 %f3
+}
+
+; func runtime.strhash(p *string, seed uint64) uint64
+;
+;    strhash returns the hash of the contents of the string at p.
+define %uint64 @runtime.strhash(i8* %p, %uint64 %seed) {
+entry:
+	%sp = bitcast i8* %p to %string*
+	%s = load %string, %string* %sp
+	%data = extractvalue %string %s, 0
+	%len = extractvalue %string %s, 1
+	%h = call %uint64 @runtime.memhash(i8* %data, %int %len, %uint64 %seed)
+	ret %uint64 %h
+}
+
+; Zero floating-point value, used to hash both +0 and -0 alike.
+@runtime.zerofloat = private unnamed_addr constant double 0.0
+
+; func runtime.f32hash(p *float32, seed uint64) uint64
+;
+;    f32hash returns the hash of the float32 at p. Positive and negative zero
+;    hash alike, and NaNs hash randomly.
+define %uint64 @runtime.f32hash(i8* %p, %uint64 %seed) {
+entry:
+	%fp = bitcast i8* %p to %float32*
+	%f = load %float32, %float32* %fp
+	%is_zero = fcmp oeq %float32 %f, 0.0
+	br i1 %is_zero, label %zero, label %check_nan
+
+zero:
+	%zp = bitcast double* @runtime.zerofloat to i8*
+	%zh = call %uint64 @runtime.memhash(i8* %zp, %int 4, %uint64 %seed)
+	ret %uint64 %zh
+
+check_nan:
+	%is_nan = fcmp uno %float32 %f, %f
+	br i1 %is_nan, label %nan, label %value
+
+nan:
+	%r = call %uint64 @runtime.fastrand()
+	%nh = xor %uint64 %seed, %r
+	ret %uint64 %nh
+
+value:
+	%vh = call %uint64 @runtime.memhash(i8* %p, %int 4, %uint64 %seed)
+	ret %uint64 %vh
+}
+
+; func runtime.f64hash(p *float64, seed uint64) uint64
+;
+;    f64hash returns the hash of the float64 at p. Positive and negative zero
+;    hash alike, and NaNs hash randomly.
+define %uint64 @runtime.f64hash(i8* %p, %uint64 %seed) {
+entry:
+	%fp = bitcast i8* %p to %float64*
+	%f = load %float64, %float64* %fp
+	%is_zero = fcmp oeq %float64 %f, 0.0
+	br i1 %is_zero, label %zero, label %check_nan
+
+zero:
+	%zp = bitcast double* @runtime.zerofloat to i8*
+	%zh = call %uint64 @runtime.memhash(i8* %zp, %int 8, %uint64 %seed)
+	ret %uint64 %zh
+
+check_nan:
+	%is_nan = fcmp uno %float64 %f, %f
+	br i1 %is_nan, label %nan, label %value
+
+nan:
+	%r = call %uint64 @runtime.fastrand()
+	%nh = xor %uint64 %seed, %r
+	ret %uint64 %nh
+
+value:
+	%vh = call %uint64 @runtime.memhash(i8* %p, %int 8, %uint64 %seed)
+	ret %uint64 %vh
+}
+
+; func runtime.interhash(p *interface{}, seed uint64) uint64
+;
+;    interhash returns the hash of the interface value at p, based on the hash
+;    of its dynamic value. Hashing an interface value holding an unhashable
+;    type panics.
+define %uint64 @runtime.interhash(i8* %p, %uint64 %seed) {
+entry:
+	%ip = bitcast i8* %p to { %runtime.itab*, i8* }*
+	%tab_ptr = getelementptr { %runtime.itab*, i8* }, { %runtime.itab*, i8* }* %ip, i64 0, i32 0
+	%tab = load %runtime.itab*, %runtime.itab** %tab_ptr
+	%data_ptr = getelementptr { %runtime.itab*, i8* }, { %runtime.itab*, i8* }* %ip, i64 0, i32 1
+	%is_nil = icmp eq %runtime.itab* %tab, null
+	br i1 %is_nil, label %ret_nil, label %check_hashable
+
+ret_nil:
+	ret %uint64 %seed
+
+check_hashable:
+	%typ_ptr = getelementptr %runtime.itab, %runtime.itab* %tab, i64 0, i32 1
+	%typ = load %runtime._type*, %runtime._type** %typ_ptr
+	%hash_ptr = getelementptr %runtime._type, %runtime._type* %typ, i64 0, i32 4
+	%hash = load %uint64 (i8*, %uint64)*, %uint64 (i8*, %uint64)** %hash_ptr
+	%unhashable = icmp eq %uint64 (i8*, %uint64)* %hash, null
+	br i1 %unhashable, label %fail, label %hash_value
+
+hash_value:
+	%value_ptr = call i8* @runtime.ifacedataptr(%runtime._type* %typ, i8** %data_ptr)
+	%h = call %uint64 %hash(i8* %value_ptr, %uint64 %seed)
+	ret %uint64 %h
+
+fail:
+	call void @runtime.panicunhashable(%runtime._type* %typ)
+	unreachable
+}
+
+; === [ maps ] =================================================================
+
+; void free(void *ptr)
+declare void @free(i8* %ptr)
+
+; declare void @llvm.memcpy.p0i8.p0i8.i64(i8* <dest>, i8* <src>, i64 <len>, i1 <isvolatile>)
+declare void @llvm.memcpy.p0i8.p0i8.i64(i8* %dest, i8* %src, i64 %len, i1 %isvolatile)
+
+; Maps are implemented as hash tables with separate chaining. The map entries
+; are never moved once inserted; growing the hash table only relinks the
+; entries into the new buckets.
+;
+; The key of a map entry is stored after the 24 byte map entry header
+; (%runtime.mapentry), and the element is stored after the key (aligned to 8
+; bytes).
+
+; func runtime.makemap(key, elem *_type, hint int) *hmap
+;
+;    makemap returns a new map with the given key and element types, with
+;    room for approximately hint entries.
+define %runtime.hmap* @runtime.makemap(%runtime._type* %key, %runtime._type* %elem, %int %hint) {
+entry:
+	br label %size.cond
+
+	; for nbuckets := 8; nbuckets < hint; nbuckets <<= 1
+size.cond:
+	%nbuckets = phi %int [ 8, %entry ], [ %nbuckets.next, %size.body ]
+	%cond = icmp slt %int %nbuckets, %hint
+	br i1 %cond, label %size.body, label %size.exit
+
+size.body:
+	%nbuckets.next = shl %int %nbuckets, 1
+	br label %size.cond
+
+size.exit:
+	%mem = call i8* @calloc(%uint64 1, %uint64 48)
+	%h = bitcast i8* %mem to %runtime.hmap*
+	%buckets_mem = call i8* @calloc(%uint64 %nbuckets, %uint64 8)
+	%buckets = bitcast i8* %buckets_mem to %runtime.mapentry**
+	%nbuckets_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 1
+	store %int %nbuckets, %int* %nbuckets_ptr
+	%buckets_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 2
+	store %runtime.mapentry** %buckets, %runtime.mapentry*** %buckets_ptr
+	%key_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 3
+	store %runtime._type* %key, %runtime._type** %key_ptr
+	%elem_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 4
+	store %runtime._type* %elem, %runtime._type** %elem_ptr
+	%seed = call %uint64 @runtime.fastrand()
+	%seed_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 5
+	store %uint64 %seed, %uint64* %seed_ptr
+	ret %runtime.hmap* %h
+}
+
+; func runtime.mapentrykey(e *mapentry) unsafe.Pointer
+;
+;    mapentrykey returns a pointer to the key of the map entry e.
+define i8* @runtime.mapentrykey(%runtime.mapentry* %e) {
+entry:
+	%p = bitcast %runtime.mapentry* %e to i8*
+	%key = getelementptr i8, i8* %p, i64 24
+	ret i8* %key
+}
+
+; func runtime.mapentryelem(h *hmap, e *mapentry) unsafe.Pointer
+;
+;    mapentryelem returns a pointer to the element of the map entry e of h.
+define i8* @runtime.mapentryelem(%runtime.hmap* %h, %runtime.mapentry* %e) {
+entry:
+	%key = call i8* @runtime.mapentrykey(%runtime.mapentry* %e)
+	%keysize = call %int @runtime.mapkeysize(%runtime.hmap* %h)
+	%elem = getelementptr i8, i8* %key, %int %keysize
+	ret i8* %elem
+}
+
+; func runtime.mapkeysize(h *hmap) int
+;
+;    mapkeysize returns the size of keys of h, aligned to 8 bytes.
+define %int @runtime.mapkeysize(%runtime.hmap* %h) {
+entry:
+	%key_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 3
+	%key = load %runtime._type*, %runtime._type** %key_ptr
+	%size_ptr = getelementptr %runtime._type, %runtime._type* %key, i64 0, i32 1
+	%size = load %int, %int* %size_ptr
+	%size_add = add %int %size, 7
+	%aligned = and %int %size_add, -8
+	ret %int %aligned
+}
+
+; func runtime.maphash(h *hmap, key unsafe.Pointer) uint64
+;
+;    maphash returns the hash of the key at the given address, using the hash
+;    function of the key type and the seed of h.
+define %uint64 @runtime.maphash(%runtime.hmap* %h, i8* %key) {
+entry:
+	%key_type_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 3
+	%key_type = load %runtime._type*, %runtime._type** %key_type_ptr
+	%hash_ptr = getelementptr %runtime._type, %runtime._type* %key_type, i64 0, i32 4
+	%hash = load %uint64 (i8*, %uint64)*, %uint64 (i8*, %uint64)** %hash_ptr
+	%seed_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 5
+	%seed = load %uint64, %uint64* %seed_ptr
+	%result = call %uint64 %hash(i8* %key, %uint64 %seed)
+	ret %uint64 %result
+}
+
+; func runtime.mapbucket(h *hmap, hash uint64) **mapentry
+;
+;    mapbucket returns the address of the bucket of h holding keys with the given
+;    hash.
+define %runtime.mapentry** @runtime.mapbucket(%runtime.hmap* %h, %uint64 %hash) {
+entry:
+	%nbuckets_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 1
+	%nbuckets = load %int, %int* %nbuckets_ptr
+	%mask = sub %int %nbuckets, 1
+	%index = and %uint64 %hash, %mask
+	%buckets_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 2
+	%buckets = load %runtime.mapentry**, %runtime.mapentry*** %buckets_ptr
+	%bucket = getelementptr %runtime.mapentry*, %runtime.mapentry** %buckets, %uint64 %index
+	ret %runtime.mapentry** %bucket
+}
+
+; func runtime.mapfind(h *hmap, key unsafe.Pointer, hash uint64) *mapentry
+;
+;    mapfind returns the map entry of h with the given key and hash, or nil if
+;    not present.
+define %runtime.mapentry* @runtime.mapfind(%runtime.hmap* %h, i8* %key, %uint64 %hash) {
+entry:
+	%key_type_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 3
+	%key_type = load %runtime._type*, %runtime._type** %key_type_ptr
+	%equal_ptr = getelementptr %runtime._type, %runtime._type* %key_type, i64 0, i32 3
+	%equal = load i1 (i8*, i8*)*, i1 (i8*, i8*)** %equal_ptr
+	%bucket = call %runtime.mapentry** @runtime.mapbucket(%runtime.hmap* %h, %uint64 %hash)
+	%first = load %runtime.mapentry*, %runtime.mapentry** %bucket
+	br label %loop.cond
+
+loop.cond:
+	%e = phi %runtime.mapentry* [ %first, %entry ], [ %next, %loop.post ]
+	%at_end = icmp eq %runtime.mapentry* %e, null
+	br i1 %at_end, label %ret_nil, label %check_hash
+
+check_hash:
+	%e_hash_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %e, i64 0, i32 1
+	%e_hash = load %uint64, %uint64* %e_hash_ptr
+	%same_hash = icmp eq %uint64 %e_hash, %hash
+	br i1 %same_hash, label %check_key, label %loop.post
+
+check_key:
+	%e_key = call i8* @runtime.mapentrykey(%runtime.mapentry* %e)
+	%same_key = call i1 %equal(i8* %e_key, i8* %key)
+	br i1 %same_key, label %ret_entry, label %loop.post
+
+loop.post:
+	%next_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %e, i64 0, i32 0
+	%next = load %runtime.mapentry*, %runtime.mapentry** %next_ptr
+	br label %loop.cond
+
+ret_nil:
+	ret %runtime.mapentry* null
+
+ret_entry:
+	ret %runtime.mapentry* %e
+}
+
+; func runtime.mapaccess(h *hmap, key unsafe.Pointer) unsafe.Pointer
+;
+;    mapaccess returns a pointer to the element of h with the given key, or nil
+;    if not present.
+define i8* @runtime.mapaccess(%runtime.hmap* %h, i8* %key) {
+entry:
+	%is_nil = icmp eq %runtime.hmap* %h, null
+	br i1 %is_nil, label %ret_nil, label %lookup
+
+lookup:
+	%hash = call %uint64 @runtime.maphash(%runtime.hmap* %h, i8* %key)
+	%e = call %runtime.mapentry* @runtime.mapfind(%runtime.hmap* %h, i8* %key, %uint64 %hash)
+	%found = icmp ne %runtime.mapentry* %e, null
+	br i1 %found, label %ret_elem, label %ret_nil
+
+ret_elem:
+	%elem = call i8* @runtime.mapentryelem(%runtime.hmap* %h, %runtime.mapentry* %e)
+	ret i8* %elem
+
+ret_nil:
+	ret i8* null
+}
+
+; func runtime.mapassign(h *hmap, key unsafe.Pointer) unsafe.Pointer
+;
+;    mapassign returns a pointer to the element of h with the given key, for
+;    assignment. A new map entry is inserted if the key is not present.
+;    Assignment to entries of nil maps panics.
+define i8* @runtime.mapassign(%runtime.hmap* %h, i8* %key) {
+entry:
+	%is_nil = icmp eq %runtime.hmap* %h, null
+	br i1 %is_nil, label %panic_nil, label %lookup
+
+panic_nil:
+	%nil_map = load %string, %string* @"runtime.str.nil_map"
+	call void @runtime.panicerror(%string %nil_map)
+	unreachable
+
+lookup:
+	%hash = call %uint64 @runtime.maphash(%runtime.hmap* %h, i8* %key)
+	%e = call %runtime.mapentry* @runtime.mapfind(%runtime.hmap* %h, i8* %key, %uint64 %hash)
+	%found = icmp ne %runtime.mapentry* %e, null
+	br i1 %found, label %ret_elem, label %check_grow
+
+ret_elem:
+	%elem = call i8* @runtime.mapentryelem(%runtime.hmap* %h, %runtime.mapentry* %e)
+	ret i8* %elem
+
+	; Grow hash table if the load factor exceeds 6.5 entries per bucket.
+check_grow:
+	%count_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 0
+	%count = load %int, %int* %count_ptr
+	%nbuckets_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 1
+	%nbuckets = load %int, %int* %nbuckets_ptr
+	%count_inc = add %int %count, 1
+	%count_x2 = mul %int %count_inc, 2
+	%nbuckets_x13 = mul %int %nbuckets, 13
+	%overload = icmp sgt %int %count_x2, %nbuckets_x13
+	br i1 %overload, label %grow, label %insert
+
+grow:
+	call void @runtime.growmap(%runtime.hmap* %h)
+	br label %insert
+
+insert:
+	; size = sizeof(mapentry) + keysize + elemsize
+	%keysize = call %int @runtime.mapkeysize(%runtime.hmap* %h)
+	%elem_type_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 4
+	%elem_type = load %runtime._type*, %runtime._type** %elem_type_ptr
+	%elemsize_ptr = getelementptr %runtime._type, %runtime._type* %elem_type, i64 0, i32 1
+	%elemsize = load %int, %int* %elemsize_ptr
+	%size_key = add %int %keysize, 24
+	%size = add %int %size_key, %elemsize
+	%mem = call i8* @calloc(%uint64 1, %uint64 %size)
+	%new = bitcast i8* %mem to %runtime.mapentry*
+	%new_hash_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %new, i64 0, i32 1
+	store %uint64 %hash, %uint64* %new_hash_ptr
+	%new_key = call i8* @runtime.mapentrykey(%runtime.mapentry* %new)
+	%key_type_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 3
+	%key_type = load %runtime._type*, %runtime._type** %key_type_ptr
+	%key_type_size_ptr = getelementptr %runtime._type, %runtime._type* %key_type, i64 0, i32 1
+	%key_type_size = load %int, %int* %key_type_size_ptr
+	call void @llvm.memcpy.p0i8.p0i8.i64(i8* %new_key, i8* %key, i64 %key_type_size, i1 false)
+	; Insert map entry at the head of its bucket.
+	%bucket = call %runtime.mapentry** @runtime.mapbucket(%runtime.hmap* %h, %uint64 %hash)
+	%first = load %runtime.mapentry*, %runtime.mapentry** %bucket
+	%new_next_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %new, i64 0, i32 0
+	store %runtime.mapentry* %first, %runtime.mapentry** %new_next_ptr
+	store %runtime.mapentry* %new, %runtime.mapentry** %bucket
+	store %int %count_inc, %int* %count_ptr
+	%new_elem = call i8* @runtime.mapentryelem(%runtime.hmap* %h, %runtime.mapentry* %new)
+	ret i8* %new_elem
+}
+
+; func runtime.growmap(h *hmap)
+;
+;    growmap doubles the number of buckets of h, relinking the map entries into
+;    the new buckets.
+define void @runtime.growmap(%runtime.hmap* %h) {
+entry:
+	%nbuckets_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 1
+	%nbuckets = load %int, %int* %nbuckets_ptr
+	%buckets_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 2
+	%buckets = load %runtime.mapentry**, %runtime.mapentry*** %buckets_ptr
+	%new_nbuckets = shl %int %nbuckets, 1
+	%new_mask = sub %int %new_nbuckets, 1
+	%new_buckets_mem = call i8* @calloc(%uint64 %new_nbuckets, %uint64 8)
+	%new_buckets = bitcast i8* %new_buckets_mem to %runtime.mapentry**
+	br label %outer.cond
+
+	; for i := 0; i < nbuckets; i++
+outer.cond:
+	%i = phi %int [ 0, %entry ], [ %i.inc, %inner.exit ]
+	%i_cond = icmp slt %int %i, %nbuckets
+	br i1 %i_cond, label %outer.body, label %outer.exit
+
+outer.body:
+	%bucket = getelementptr %runtime.mapentry*, %runtime.mapentry** %buckets, %int %i
+	%first = load %runtime.mapentry*, %runtime.mapentry** %bucket
+	br label %inner.cond
+
+	; for e := buckets[i]; e != nil; e = next
+inner.cond:
+	%e = phi %runtime.mapentry* [ %first, %outer.body ], [ %next, %inner.body ]
+	%at_end = icmp eq %runtime.mapentry* %e, null
+	br i1 %at_end, label %inner.exit, label %inner.body
+
+inner.body:
+	%next_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %e, i64 0, i32 0
+	%next = load %runtime.mapentry*, %runtime.mapentry** %next_ptr
+	%hash_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %e, i64 0, i32 1
+	%hash = load %uint64, %uint64* %hash_ptr
+	%index = and %uint64 %hash, %new_mask
+	%new_bucket = getelementptr %runtime.mapentry*, %runtime.mapentry** %new_buckets, %uint64 %index
+	%new_first = load %runtime.mapentry*, %runtime.mapentry** %new_bucket
+	store %runtime.mapentry* %new_first, %runtime.mapentry** %next_ptr
+	store %runtime.mapentry* %e, %runtime.mapentry** %new_bucket
+	br label %inner.cond
+
+inner.exit:
+	%i.inc = add %int %i, 1
+	br label %outer.cond
+
+outer.exit:
+	store %int %new_nbuckets, %int* %nbuckets_ptr
+	store %runtime.mapentry** %new_buckets, %runtime.mapentry*** %buckets_ptr
+	%old_buckets_mem = bitcast %runtime.mapentry** %buckets to i8*
+	call void @free(i8* %old_buckets_mem)
+	ret void
+}
+
+; func runtime.mapdelete(h *hmap, key unsafe.Pointer)
+;
+;    mapdelete removes the entry with the given key from h, if present. The map
+;    entry is marked as deleted, as it may still be referenced by iterators.
+define void @runtime.mapdelete(%runtime.hmap* %h, i8* %key) {
+entry:
+	%is_nil = icmp eq %runtime.hmap* %h, null
+	br i1 %is_nil, label %ret, label %lookup
+
+lookup:
+	%key_type_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 3
+	%key_type = load %runtime._type*, %runtime._type** %key_type_ptr
+	%equal_ptr = getelementptr %runtime._type, %runtime._type* %key_type, i64 0, i32 3
+	%equal = load i1 (i8*, i8*)*, i1 (i8*, i8*)** %equal_ptr
+	%hash = call %uint64 @runtime.maphash(%runtime.hmap* %h, i8* %key)
+	%bucket = call %runtime.mapentry** @runtime.mapbucket(%runtime.hmap* %h, %uint64 %hash)
+	br label %loop.cond
+
+	; for pp := bucket; *pp != nil; pp = &(*pp).next
+loop.cond:
+	%pp = phi %runtime.mapentry** [ %bucket, %lookup ], [ %next_ptr, %loop.post ]
+	%e = load %runtime.mapentry*, %runtime.mapentry** %pp
+	%at_end = icmp eq %runtime.mapentry* %e, null
+	br i1 %at_end, label %ret, label %check_hash
+
+check_hash:
+	%next_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %e, i64 0, i32 0
+	%e_hash_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %e, i64 0, i32 1
+	%e_hash = load %uint64, %uint64* %e_hash_ptr
+	%same_hash = icmp eq %uint64 %e_hash, %hash
+	br i1 %same_hash, label %check_key, label %loop.post
+
+check_key:
+	%e_key = call i8* @runtime.mapentrykey(%runtime.mapentry* %e)
+	%same_key = call i1 %equal(i8* %e_key, i8* %key)
+	br i1 %same_key, label %remove, label %loop.post
+
+loop.post:
+	br label %loop.cond
+
+remove:
+	%next = load %runtime.mapentry*, %runtime.mapentry** %next_ptr
+	store %runtime.mapentry* %next, %runtime.mapentry** %pp
+	%deleted_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %e, i64 0, i32 2
+	store %bool true, %bool* %deleted_ptr
+	%count_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 0
+	%count = load %int, %int* %count_ptr
+	%count_dec = sub %int %count, 1
+	store %int %count_dec, %int* %count_ptr
+	br label %ret
+
+ret:
+	ret void
+}
+
+; func runtime.maplen(h *hmap) int
+;
+;    maplen returns the number of entries of h.
+define %int @runtime.maplen(%runtime.hmap* %h) {
+entry:
+	%is_nil = icmp eq %runtime.hmap* %h, null
+	br i1 %is_nil, label %ret_zero, label %ret_count
+
+ret_zero:
+	ret %int 0
+
+ret_count:
+	%count_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 0
+	%count = load %int, %int* %count_ptr
+	ret %int %count
+}
+
+; === [ map iteration ] ========================================================
+
+; Map iterators hold a snapshot of the map entries present when the iteration
+; started, as collected from a random starting bucket. Since map entries are
+; never moved nor freed, the snapshot remains valid when the map is modified
+; during iteration. Entries deleted during iteration are skipped (as they are
+; marked as deleted), and entries inserted during iteration are not produced;
+; both as permitted by the Go specification.
+
+; func runtime.mapiterinit(h *hmap) *hiter
+;
+;    mapiterinit returns a new iterator over the entries of h.
+define %runtime.hiter* @runtime.mapiterinit(%runtime.hmap* %h) {
+entry:
+	%mem = call i8* @calloc(%uint64 1, %uint64 32)
+	%it = bitcast i8* %mem to %runtime.hiter*
+	%h_ptr = getelementptr %runtime.hiter, %runtime.hiter* %it, i64 0, i32 0
+	store %runtime.hmap* %h, %runtime.hmap** %h_ptr
+	%count = call %int @runtime.maplen(%runtime.hmap* %h)
+	%empty = icmp eq %int %count, 0
+	br i1 %empty, label %ret, label %snapshot
+
+snapshot:
+	%entries_mem = call i8* @calloc(%uint64 %count, %uint64 8)
+	%entries = bitcast i8* %entries_mem to %runtime.mapentry**
+	%entries_ptr = getelementptr %runtime.hiter, %runtime.hiter* %it, i64 0, i32 1
+	store %runtime.mapentry** %entries, %runtime.mapentry*** %entries_ptr
+	%nbuckets_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 1
+	%nbuckets = load %int, %int* %nbuckets_ptr
+	%mask = sub %int %nbuckets, 1
+	%buckets_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 2
+	%buckets = load %runtime.mapentry**, %runtime.mapentry*** %buckets_ptr
+	; Randomize the iteration order by starting at a random bucket.
+	%r = call %uint64 @runtime.fastrand()
+	%start = and %uint64 %r, %mask
+	br label %outer.cond
+
+	; for j := 0; j < nbuckets; j++
+outer.cond:
+	%j = phi %int [ 0, %snapshot ], [ %j.inc, %inner.exit ]
+	%n = phi %int [ 0, %snapshot ], [ %n.inner, %inner.exit ]
+	%j_cond = icmp slt %int %j, %nbuckets
+	br i1 %j_cond, label %outer.body, label %outer.exit
+
+outer.body:
+	%start_j = add %int %start, %j
+	%index = and %int %start_j, %mask
+	%bucket = getelementptr %runtime.mapentry*, %runtime.mapentry** %buckets, %int %index
+	%first = load %runtime.mapentry*, %runtime.mapentry** %bucket
+	br label %inner.cond
+
+	; for e := buckets[(start+j)&mask]; e != nil; e = e.next
+inner.cond:
+	%e = phi %runtime.mapentry* [ %first, %outer.body ], [ %next, %inner.body ]
+	%n.inner = phi %int [ %n, %outer.body ], [ %n.inc, %inner.body ]
+	%at_end = icmp eq %runtime.mapentry* %e, null
+	br i1 %at_end, label %inner.exit, label %inner.body
+
+inner.body:
+	%slot = getelementptr %runtime.mapentry*, %runtime.mapentry** %entries, %int %n.inner
+	store %runtime.mapentry* %e, %runtime.mapentry** %slot
+	%n.inc = add %int %n.inner, 1
+	%next_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %e, i64 0, i32 0
+	%next = load %runtime.mapentry*, %runtime.mapentry** %next_ptr
+	br label %inner.cond
+
+inner.exit:
+	%j.inc = add %int %j, 1
+	br label %outer.cond
+
+outer.exit:
+	%n_ptr = getelementptr %runtime.hiter, %runtime.hiter* %it, i64 0, i32 2
+	store %int %n, %int* %n_ptr
+	br label %ret
+
+ret:
+	ret %runtime.hiter* %it
+}
+
+; func runtime.mapiternext(it *hiter, key, elem unsafe.Pointer) bool
+;
+;    mapiternext advances the iterator it to the next map entry not deleted,
+;    copying its key and element to the given addresses (if non-nil). The
+;    boolean result reports whether an entry was produced.
+define %bool @runtime.mapiternext(%runtime.hiter* %it, i8* %key, i8* %elem) {
+entry:
+	%h_ptr = getelementptr %runtime.hiter, %runtime.hiter* %it, i64 0, i32 0
+	%h = load %runtime.hmap*, %runtime.hmap** %h_ptr
+	%entries_ptr = getelementptr %runtime.hiter, %runtime.hiter* %it, i64 0, i32 1
+	%entries = load %runtime.mapentry**, %runtime.mapentry*** %entries_ptr
+	%n_ptr = getelementptr %runtime.hiter, %runtime.hiter* %it, i64 0, i32 2
+	%n = load %int, %int* %n_ptr
+	%i_ptr = getelementptr %runtime.hiter, %runtime.hiter* %it, i64 0, i32 3
+	br label %loop.cond
+
+loop.cond:
+	%i = load %int, %int* %i_ptr
+	%more = icmp slt %int %i, %n
+	br i1 %more, label %loop.body, label %done
+
+loop.body:
+	%slot = getelementptr %runtime.mapentry*, %runtime.mapentry** %entries, %int %i
+	%e = load %runtime.mapentry*, %runtime.mapentry** %slot
+	%i.inc = add %int %i, 1
+	store %int %i.inc, %int* %i_ptr
+	%deleted_ptr = getelementptr %runtime.mapentry, %runtime.mapentry* %e, i64 0, i32 2
+	%deleted = load %bool, %bool* %deleted_ptr
+	br i1 %deleted, label %loop.cond, label %copy_key
+
+copy_key:
+	%has_key = icmp ne i8* %key, null
+	br i1 %has_key, label %copy_key.body, label %copy_elem
+
+copy_key.body:
+	%e_key = call i8* @runtime.mapentrykey(%runtime.mapentry* %e)
+	%key_type_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 3
+	%key_type = load %runtime._type*, %runtime._type** %key_type_ptr
+	%key_size_ptr = getelementptr %runtime._type, %runtime._type* %key_type, i64 0, i32 1
+	%key_size = load %int, %int* %key_size_ptr
+	call void @llvm.memcpy.p0i8.p0i8.i64(i8* %key, i8* %e_key, i64 %key_size, i1 false)
+	br label %copy_elem
+
+copy_elem:
+	%has_elem = icmp ne i8* %elem, null
+	br i1 %has_elem, label %copy_elem.body, label %ret_true
+
+copy_elem.body:
+	%e_elem = call i8* @runtime.mapentryelem(%runtime.hmap* %h, %runtime.mapentry* %e)
+	%elem_type_ptr = getelementptr %runtime.hmap, %runtime.hmap* %h, i64 0, i32 4
+	%elem_type = load %runtime._type*, %runtime._type** %elem_type_ptr
+	%elem_size_ptr = getelementptr %runtime._type, %runtime._type* %elem_type, i64 0, i32 1
+	%elem_size = load %int, %int* %elem_size_ptr
+	call void @llvm.memcpy.p0i8.p0i8.i64(i8* %elem, i8* %e_elem, i64 %elem_size, i1 false)
+	br label %ret_true
+
+ret_true:
+	ret %bool true
+
+done:
+	ret %bool false
+}
+
+; === [ strings ] ==============================================================
+
+; func runtime.decoderune(s string, k int) (r rune, pos int)
+;
+;    decoderune returns the rune at byte offset k of s and the byte offset of
+;    the next rune, decoding UTF-8 the same way as the Go runtime. Invalid UTF-8
+;    sequences are decoded as U+FFFD (RuneError) of width 1.
+;
+;    Pre-condition: 0 <= k < len(s).
+define { %int32, %int } @runtime.decoderune(%string %s, %int %k) {
+entry:
+	%data = extractvalue %string %s, 0
+	%len = extractvalue %string %s, 1
+	; n = len(s[k:])
+	%n = sub %int %len, %k
+	%p0 = getelementptr %uint8, %uint8* %data, %int %k
+	%b0 = load %uint8, %uint8* %p0
+	%c0 = zext %uint8 %b0 to %int32
+	%pos1 = add %int %k, 1
+	%pos2 = add %int %k, 2
+	%pos3 = add %int %k, 3
+	%pos4 = add %int %k, 4
+	%p1 = getelementptr %uint8, %uint8* %p0, i64 1
+	%p2 = getelementptr %uint8, %uint8* %p0, i64 2
+	%p3 = getelementptr %uint8, %uint8* %p0, i64 3
+	%is_ascii = icmp ult %int32 %c0, 128
+	br i1 %is_ascii, label %ret_ascii, label %check2
+
+ret_ascii:
+	%ascii.0 = insertvalue { %int32, %int } zeroinitializer, %int32 %c0, 0
+	%ascii.1 = insertvalue { %int32, %int } %ascii.0, %int %pos1, 1
+	ret { %int32, %int } %ascii.1
+
+	; 2-byte sequence: 0xC0 <= s[0] < 0xE0
+check2:
+	%ge_c0 = icmp uge %int32 %c0, 192
+	%lt_e0 = icmp ult %int32 %c0, 224
+	%is2 = and i1 %ge_c0, %lt_e0
+	br i1 %is2, label %two, label %check3
+
+two:
+	%has2 = icmp sgt %int %n, 1
+	br i1 %has2, label %two.cont, label %invalid
+
+two.cont:
+	%two.b1 = load %uint8, %uint8* %p1
+	%two.c1 = zext %uint8 %two.b1 to %int32
+	%two.k1 = and %int32 %two.c1, 192
+	%two.ok1 = icmp eq %int32 %two.k1, 128
+	br i1 %two.ok1, label %two.decode, label %invalid
+
+two.decode:
+	%two.x0 = and %int32 %c0, 31
+	%two.x0s = shl %int32 %two.x0, 6
+	%two.x1 = and %int32 %two.c1, 63
+	%two.r = or %int32 %two.x0s, %two.x1
+	; reject overlong encodings.
+	%two.valid = icmp ugt %int32 %two.r, 127
+	br i1 %two.valid, label %ret2, label %invalid
+
+ret2:
+	%two.ret.0 = insertvalue { %int32, %int } zeroinitializer, %int32 %two.r, 0
+	%two.ret.1 = insertvalue { %int32, %int } %two.ret.0, %int %pos2, 1
+	ret { %int32, %int } %two.ret.1
+
+	; 3-byte sequence: 0xE0 <= s[0] < 0xF0
+check3:
+	%ge_e0 = icmp uge %int32 %c0, 224
+	%lt_f0 = icmp ult %int32 %c0, 240
+	%is3 = and i1 %ge_e0, %lt_f0
+	br i1 %is3, label %three, label %check4
+
+three:
+	%has3 = icmp sgt %int %n, 2
+	br i1 %has3, label %three.cont, label %invalid
+
+three.cont:
+	%three.b1 = load %uint8, %uint8* %p1
+	%three.c1 = zext %uint8 %three.b1 to %int32
+	%three.k1 = and %int32 %three.c1, 192
+	%three.ok1 = icmp eq %int32 %three.k1, 128
+	%three.b2 = load %uint8, %uint8* %p2
+	%three.c2 = zext %uint8 %three.b2 to %int32
+	%three.k2 = and %int32 %three.c2, 192
+	%three.ok2 = icmp eq %int32 %three.k2, 128
+	%three.ok = and i1 %three.ok1, %three.ok2
+	br i1 %three.ok, label %three.decode, label %invalid
+
+three.decode:
+	%three.x0 = and %int32 %c0, 15
+	%three.x0s = shl %int32 %three.x0, 12
+	%three.x1 = and %int32 %three.c1, 63
+	%three.x1s = shl %int32 %three.x1, 6
+	%three.x2 = and %int32 %three.c2, 63
+	%three.r01 = or %int32 %three.x0s, %three.x1s
+	%three.r = or %int32 %three.r01, %three.x2
+	; reject overlong encodings and surrogate halves (U+D800 to U+DFFF).
+	%three.not_overlong = icmp ugt %int32 %three.r, 2047
+	%three.ge_d800 = icmp uge %int32 %three.r, 55296
+	%three.le_dfff = icmp ule %int32 %three.r, 57343
+	%three.surrogate = and i1 %three.ge_d800, %three.le_dfff
+	%three.not_surrogate = xor i1 %three.surrogate, true
+	%three.valid = and i1 %three.not_overlong, %three.not_surrogate
+	br i1 %three.valid, label %ret3, label %invalid
+
+ret3:
+	%three.ret.0 = insertvalue { %int32, %int } zeroinitializer, %int32 %three.r, 0
+	%three.ret.1 = insertvalue { %int32, %int } %three.ret.0, %int %pos3, 1
+	ret { %int32, %int } %three.ret.1
+
+	; 4-byte sequence: 0xF0 <= s[0] < 0xF8
+check4:
+	%ge_f0 = icmp uge %int32 %c0, 240
+	%lt_f8 = icmp ult %int32 %c0, 248
+	%is4 = and i1 %ge_f0, %lt_f8
+	br i1 %is4, label %four, label %invalid
+
+four:
+	%has4 = icmp sgt %int %n, 3
+	br i1 %has4, label %four.cont, label %invalid
+
+four.cont:
+	%four.b1 = load %uint8, %uint8* %p1
+	%four.c1 = zext %uint8 %four.b1 to %int32
+	%four.k1 = and %int32 %four.c1, 192
+	%four.ok1 = icmp eq %int32 %four.k1, 128
+	%four.b2 = load %uint8, %uint8* %p2
+	%four.c2 = zext %uint8 %four.b2 to %int32
+	%four.k2 = and %int32 %four.c2, 192
+	%four.ok2 = icmp eq %int32 %four.k2, 128
+	%four.b3 = load %uint8, %uint8* %p3
+	%four.c3 = zext %uint8 %four.b3 to %int32
+	%four.k3 = and %int32 %four.c3, 192
+	%four.ok3 = icmp eq %int32 %four.k3, 128
+	%four.ok12 = and i1 %four.ok1, %four.ok2
+	%four.ok = and i1 %four.ok12, %four.ok3
+	br i1 %four.ok, label %four.decode, label %invalid
+
+four.decode:
+	%four.x0 = and %int32 %c0, 7
+	%four.x0s = shl %int32 %four.x0, 18
+	%four.x1 = and %int32 %four.c1, 63
+	%four.x1s = shl %int32 %four.x1, 12
+	%four.x2 = and %int32 %four.c2, 63
+	%four.x2s = shl %int32 %four.x2, 6
+	%four.x3 = and %int32 %four.c3, 63
+	%four.r01 = or %int32 %four.x0s, %four.x1s
+	%four.r012 = or %int32 %four.r01, %four.x2s
+	%four.r = or %int32 %four.r012, %four.x3
+	; reject overlong encodings and code points above U+10FFFF.
+	%four.not_overlong = icmp ugt %int32 %four.r, 65535
+	%four.le_max = icmp ule %int32 %four.r, 1114111
+	%four.valid = and i1 %four.not_overlong, %four.le_max
+	br i1 %four.valid, label %ret4, label %invalid
+
+ret4:
+	%four.ret.0 = insertvalue { %int32, %int } zeroinitializer, %int32 %four.r, 0
+	%four.ret.1 = insertvalue { %int32, %int } %four.ret.0, %int %pos4, 1
+	ret { %int32, %int } %four.ret.1
+
+	; return RuneError, k + 1
+invalid:
+	%invalid.0 = insertvalue { %int32, %int } zeroinitializer, %int32 65533, 0
+	%invalid.1 = insertvalue { %int32, %int } %invalid.0, %int %pos1, 1
+	ret { %int32, %int } %invalid.1
+}
+
+; func runtime.encoderune(p *byte, r rune) int
+;
+;    encoderune writes the UTF-8 encoding of r to p, which must have room for
+;    at least 4 bytes, and returns the number of bytes written. Invalid code
+;    points (surrogate halves and values outside of the Unicode range) are
+;    encoded as U+FFFD (RuneError).
+define %int @runtime.encoderune(%uint8* %p, %int32 %r) {
+entry:
+	%p1 = getelementptr %uint8, %uint8* %p, i64 1
+	%p2 = getelementptr %uint8, %uint8* %p, i64 2
+	%p3 = getelementptr %uint8, %uint8* %p, i64 3
+	; Compare as unsigned, to treat negative runes as invalid.
+	%is1 = icmp ule %int32 %r, 127
+	br i1 %is1, label %one, label %check2
+
+one:
+	%one.b0 = trunc %int32 %r to %uint8
+	store %uint8 %one.b0, %uint8* %p
+	ret %int 1
+
+check2:
+	%is2 = icmp ule %int32 %r, 2047
+	br i1 %is2, label %two, label %check_invalid
+
+two:
+	%two.x0 = lshr %int32 %r, 6
+	%two.c0 = or %int32 %two.x0, 192
+	%two.b0 = trunc %int32 %two.c0 to %uint8
+	store %uint8 %two.b0, %uint8* %p
+	%two.x1 = and %int32 %r, 63
+	%two.c1 = or %int32 %two.x1, 128
+	%two.b1 = trunc %int32 %two.c1 to %uint8
+	store %uint8 %two.b1, %uint8* %p1
+	ret %int 2
+
+check_invalid:
+	%too_large = icmp ugt %int32 %r, 1114111
+	%ge_d800 = icmp uge %int32 %r, 55296
+	%le_dfff = icmp ule %int32 %r, 57343
+	%surrogate = and i1 %ge_d800, %le_dfff
+	%invalid = or i1 %too_large, %surrogate
+	%r3 = select i1 %invalid, %int32 65533, %int32 %r
+	%is3 = icmp ule %int32 %r3, 65535
+	br i1 %is3, label %three, label %four
+
+three:
+	%three.x0 = lshr %int32 %r3, 12
+	%three.c0 = or %int32 %three.x0, 224
+	%three.b0 = trunc %int32 %three.c0 to %uint8
+	store %uint8 %three.b0, %uint8* %p
+	%three.s1 = lshr %int32 %r3, 6
+	%three.x1 = and %int32 %three.s1, 63
+	%three.c1 = or %int32 %three.x1, 128
+	%three.b1 = trunc %int32 %three.c1 to %uint8
+	store %uint8 %three.b1, %uint8* %p1
+	%three.x2 = and %int32 %r3, 63
+	%three.c2 = or %int32 %three.x2, 128
+	%three.b2 = trunc %int32 %three.c2 to %uint8
+	store %uint8 %three.b2, %uint8* %p2
+	ret %int 3
+
+four:
+	%four.x0 = lshr %int32 %r3, 18
+	%four.c0 = or %int32 %four.x0, 240
+	%four.b0 = trunc %int32 %four.c0 to %uint8
+	store %uint8 %four.b0, %uint8* %p
+	%four.s1 = lshr %int32 %r3, 12
+	%four.x1 = and %int32 %four.s1, 63
+	%four.c1 = or %int32 %four.x1, 128
+	%four.b1 = trunc %int32 %four.c1 to %uint8
+	store %uint8 %four.b1, %uint8* %p1
+	%four.s2 = lshr %int32 %r3, 6
+	%four.x2 = and %int32 %four.s2, 63
+	%four.c2 = or %int32 %four.x2, 128
+	%four.b2 = trunc %int32 %four.c2 to %uint8
+	store %uint8 %four.b2, %uint8* %p2
+	%four.x3 = and %int32 %r3, 63
+	%four.c3 = or %int32 %four.x3, 128
+	%four.b3 = trunc %int32 %four.c3 to %uint8
+	store %uint8 %four.b3, %uint8* %p3
+	ret %int 4
+}
+
+; func runtime.intstring(v int64) string
+;
+;    intstring returns the UTF-8 encoding of the code point v, as by
+;    string(v). Values outside of the valid Unicode range yield "�".
+define %string @runtime.intstring(%int64 %v) {
+entry:
+	%neg = icmp slt %int64 %v, 0
+	%too_large = icmp sgt %int64 %v, 1114111
+	%invalid = or i1 %neg, %too_large
+	%v32 = trunc %int64 %v to %int32
+	%r = select i1 %invalid, %int32 65533, %int32 %v32
+	%buf = call i8* @calloc(%uint64 1, %uint64 4)
+	%n = call %int @runtime.encoderune(%uint8* %buf, %int32 %r)
+	%s.0 = insertvalue %string zeroinitializer, i8* %buf, 0
+	%s.1 = insertvalue %string %s.0, %int %n, 1
+	ret %string %s.1
+}
+
+; func runtime.stringtoslicebyte(s string) []byte
+;
+;    stringtoslicebyte returns a new byte slice holding a copy of the contents
+;    of s.
+define %"[]%uint8" @runtime.stringtoslicebyte(%string %s) {
+entry:
+	%data = extractvalue %string %s, 0
+	%len = extractvalue %string %s, 1
+	%buf = call i8* @calloc(%uint64 %len, %uint64 1)
+	call void @llvm.memcpy.p0i8.p0i8.i64(i8* %buf, i8* %data, i64 %len, i1 false)
+	%b.0 = insertvalue %"[]%uint8" zeroinitializer, %uint8* %buf, 0
+	%b.1 = insertvalue %"[]%uint8" %b.0, %int %len, 1
+	%b.2 = insertvalue %"[]%uint8" %b.1, %int %len, 2
+	ret %"[]%uint8" %b.2
+}
+
+; func runtime.slicebytetostring(b []byte) string
+;
+;    slicebytetostring returns a new string holding a copy of the contents of b.
+define %string @runtime.slicebytetostring(%"[]%uint8" %b) {
+entry:
+	%data = extractvalue %"[]%uint8" %b, 0
+	%len = extractvalue %"[]%uint8" %b, 1
+	%buf = call i8* @calloc(%uint64 %len, %uint64 1)
+	call void @llvm.memcpy.p0i8.p0i8.i64(i8* %buf, i8* %data, i64 %len, i1 false)
+	%s.0 = insertvalue %string zeroinitializer, i8* %buf, 0
+	%s.1 = insertvalue %string %s.0, %int %len, 1
+	ret %string %s.1
+}
+
+; func runtime.stringtoslicerune(s string) []rune
+;
+;    stringtoslicerune returns a new rune slice holding the runes of s, as
+;    decoded from UTF-8.
+define %"[]%int32" @runtime.stringtoslicerune(%string %s) {
+entry:
+	%len = extractvalue %string %s, 1
+	br label %count.cond
+
+	; Count runes.
+count.cond:
+	%count.k = phi %int [ 0, %entry ], [ %count.pos, %count.body ]
+	%count.n = phi %int [ 0, %entry ], [ %count.n.inc, %count.body ]
+	%count.more = icmp slt %int %count.k, %len
+	br i1 %count.more, label %count.body, label %alloc
+
+count.body:
+	%count.result = call { %int32, %int } @runtime.decoderune(%string %s, %int %count.k)
+	%count.pos = extractvalue { %int32, %int } %count.result, 1
+	%count.n.inc = add %int %count.n, 1
+	br label %count.cond
+
+alloc:
+	%mem = call i8* @calloc(%uint64 %count.n, %uint64 4)
+	%buf = bitcast i8* %mem to %int32*
+	br label %decode.cond
+
+	; Decode runes.
+decode.cond:
+	%decode.k = phi %int [ 0, %alloc ], [ %decode.pos, %decode.body ]
+	%decode.i = phi %int [ 0, %alloc ], [ %decode.i.inc, %decode.body ]
+	%decode.more = icmp slt %int %decode.k, %len
+	br i1 %decode.more, label %decode.body, label %done
+
+decode.body:
+	%decode.result = call { %int32, %int } @runtime.decoderune(%string %s, %int %decode.k)
+	%decode.r = extractvalue { %int32, %int } %decode.result, 0
+	%decode.pos = extractvalue { %int32, %int } %decode.result, 1
+	%decode.p = getelementptr %int32, %int32* %buf, %int %decode.i
+	store %int32 %decode.r, %int32* %decode.p
+	%decode.i.inc = add %int %decode.i, 1
+	br label %decode.cond
+
+done:
+	%r.0 = insertvalue %"[]%int32" zeroinitializer, %int32* %buf, 0
+	%r.1 = insertvalue %"[]%int32" %r.0, %int %count.n, 1
+	%r.2 = insertvalue %"[]%int32" %r.1, %int %count.n, 2
+	ret %"[]%int32" %r.2
+}
+
+; func runtime.slicerunetostring(r []rune) string
+;
+;    slicerunetostring returns a new string holding the UTF-8 encoding of the
+;    runes of r.
+define %string @runtime.slicerunetostring(%"[]%int32" %r) {
+entry:
+	%tmp = alloca [4 x %uint8]
+	%tmp_ptr = getelementptr [4 x %uint8], [4 x %uint8]* %tmp, i64 0, i64 0
+	%data = extractvalue %"[]%int32" %r, 0
+	%len = extractvalue %"[]%int32" %r, 1
+	br label %size.cond
+
+	; Compute size of UTF-8 encoding.
+size.cond:
+	%size.i = phi %int [ 0, %entry ], [ %size.i.inc, %size.body ]
+	%size.n = phi %int [ 0, %entry ], [ %size.n.inc, %size.body ]
+	%size.more = icmp slt %int %size.i, %len
+	br i1 %size.more, label %size.body, label %alloc
+
+size.body:
+	%size.p = getelementptr %int32, %int32* %data, %int %size.i
+	%size.r = load %int32, %int32* %size.p
+	%size.w = call %int @runtime.encoderune(%uint8* %tmp_ptr, %int32 %size.r)
+	%size.n.inc = add %int %size.n, %size.w
+	%size.i.inc = add %int %size.i, 1
+	br label %size.cond
+
+alloc:
+	%buf = call i8* @calloc(%uint64 %size.n, %uint64 1)
+	br label %encode.cond
+
+	; Encode runes.
+encode.cond:
+	%encode.i = phi %int [ 0, %alloc ], [ %encode.i.inc, %encode.body ]
+	%encode.n = phi %int [ 0, %alloc ], [ %encode.n.inc, %encode.body ]
+	%encode.more = icmp slt %int %encode.i, %len
+	br i1 %encode.more, label %encode.body, label %done
+
+encode.body:
+	%encode.p = getelementptr %int32, %int32* %data, %int %encode.i
+	%encode.r = load %int32, %int32* %encode.p
+	%encode.dst = getelementptr %uint8, %uint8* %buf, %int %encode.n
+	%encode.w = call %int @runtime.encoderune(%uint8* %encode.dst, %int32 %encode.r)
+	%encode.n.inc = add %int %encode.n, %encode.w
+	%encode.i.inc = add %int %encode.i, 1
+	br label %encode.cond
+
+done:
+	%s.0 = insertvalue %string zeroinitializer, i8* %buf, 0
+	%s.1 = insertvalue %string %s.0, %int %size.n, 1
+	ret %string %s.1
+}
+
+; int memcmp(const void *s1, const void *s2, size_t n)
+declare i32 @memcmp(i8* %s1, i8* %s2, i64 %n)
+
+; func runtime.strequal(x, y string) bool
+;
+;    strequal reports whether x and y are equal. The contents of the strings
+;    are only compared if their lengths are equal and their data pointers
+;    differ.
+define %bool @runtime.strequal(%string %x, %string %y) {
+entry:
+	%x_len = extractvalue %string %x, 1
+	%y_len = extractvalue %string %y, 1
+	%same_len = icmp eq %int %x_len, %y_len
+	br i1 %same_len, label %check_data, label %ret_false
+
+check_data:
+	%x_data = extractvalue %string %x, 0
+	%y_data = extractvalue %string %y, 0
+	%same_data = icmp eq i8* %x_data, %y_data
+	br i1 %same_data, label %ret_true, label %compare
+
+compare:
+	%result = call i32 @memcmp(i8* %x_data, i8* %y_data, i64 %x_len)
+	%equal = icmp eq i32 %result, 0
+	ret %bool %equal
+
+ret_true:
+	ret %bool true
+
+ret_false:
+	ret %bool false
+}
+
+; func runtime.concatstrings(a []string) string
+;
+;    concatstrings returns the concatenation of the strings of a, allocating
+;    the result once.
+define %string @runtime.concatstrings(%"[]%string" %a) {
+entry:
+	%data = extractvalue %"[]%string" %a, 0
+	%len = extractvalue %"[]%string" %a, 1
+	br label %size.cond
+
+	; Compute total length.
+size.cond:
+	%size.i = phi %int [ 0, %entry ], [ %size.i.inc, %size.body ]
+	%size.n = phi %int [ 0, %entry ], [ %size.n.inc, %size.body ]
+	%size.more = icmp slt %int %size.i, %len
+	br i1 %size.more, label %size.body, label %alloc
+
+size.body:
+	%size.p = getelementptr %string, %string* %data, %int %size.i
+	%size.s = load %string, %string* %size.p
+	%size.s_len = extractvalue %string %size.s, 1
+	%size.n.inc = add %int %size.n, %size.s_len
+	%size.i.inc = add %int %size.i, 1
+	br label %size.cond
+
+alloc:
+	%buf = call i8* @calloc(%uint64 %size.n, %uint64 1)
+	br label %copy.cond
+
+	; Copy contents.
+copy.cond:
+	%copy.i = phi %int [ 0, %alloc ], [ %copy.i.inc, %copy.body ]
+	%copy.n = phi %int [ 0, %alloc ], [ %copy.n.inc, %copy.body ]
+	%copy.more = icmp slt %int %copy.i, %len
+	br i1 %copy.more, label %copy.body, label %done
+
+copy.body:
+	%copy.p = getelementptr %string, %string* %data, %int %copy.i
+	%copy.s = load %string, %string* %copy.p
+	%copy.s_data = extractvalue %string %copy.s, 0
+	%copy.s_len = extractvalue %string %copy.s, 1
+	%copy.dst = getelementptr i8, i8* %buf, %int %copy.n
+	call void @llvm.memcpy.p0i8.p0i8.i64(i8* %copy.dst, i8* %copy.s_data, i64 %copy.s_len, i1 false)
+	%copy.n.inc = add %int %copy.n, %copy.s_len
+	%copy.i.inc = add %int %copy.i, 1
+	br label %copy.cond
+
+done:
+	%s.0 = insertvalue %string zeroinitializer, i8* %buf, 0
+	%s.1 = insertvalue %string %s.0, %int %size.n, 1
+	ret %string %s.1
+}
+
+; func runtime.concatstring2(x, y string) string
+;
+;    concatstring2 returns the concatenation of x and y.
+define %string @runtime.concatstring2(%string %x, %string %y) {
+entry:
+	%x_data = extractvalue %string %x, 0
+	%x_len = extractvalue %string %x, 1
+	%y_data = extractvalue %string %y, 0
+	%y_len = extractvalue %string %y, 1
+	%len = add %int %x_len, %y_len
+	%data = call i8* @calloc(i64 1, i64 %len)
+	call void @llvm.memcpy.p0i8.p0i8.i64(i8* %data, i8* %x_data, i64 %x_len, i1 false)
+	%y_dst = getelementptr i8, i8* %data, %int %x_len
+	call void @llvm.memcpy.p0i8.p0i8.i64(i8* %y_dst, i8* %y_data, i64 %y_len, i1 false)
+	%s.0 = insertvalue %string zeroinitializer, i8* %data, 0
+	%s.1 = insertvalue %string %s.0, %int %len, 1
+	ret %string %s.1
+}
+
+; func runtime.slicestring(s string, low int) string
+;
+;    slicestring returns s[low:].
+define %string @runtime.slicestring(%string %s, %int %low) {
+entry:
+	%data = extractvalue %string %s, 0
+	%len = extractvalue %string %s, 1
+	%new_data = getelementptr i8, i8* %data, %int %low
+	%new_len = sub %int %len, %low
+	%t.0 = insertvalue %string zeroinitializer, i8* %new_data, 0
+	%t.1 = insertvalue %string %t.0, %int %new_len, 1
+	ret %string %t.1
+}
+
+; func runtime.indexbyte(s string, c byte) int
+;
+;    indexbyte returns the index of the first instance of c in s, or -1 if c is
+;    not present in s.
+define %int @runtime.indexbyte(%string %s, %uint8 %c) {
+entry:
+	%data = extractvalue %string %s, 0
+	%len = extractvalue %string %s, 1
+	br label %loop.cond
+
+loop.cond:
+	%i = phi %int [ 0, %entry ], [ %i.inc, %loop.post ]
+	%cond = icmp slt %int %i, %len
+	br i1 %cond, label %loop.body, label %ret_none
+
+loop.body:
+	%p = getelementptr %uint8, %uint8* %data, %int %i
+	%b = load %uint8, %uint8* %p
+	%found = icmp eq %uint8 %b, %c
+	br i1 %found, label %ret_index, label %loop.post
+
+loop.post:
+	%i.inc = add %int %i, 1
+	br label %loop.cond
+
+ret_index:
+	ret %int %i
+
+ret_none:
+	ret %int -1
+}
+
+; func runtime.lastindexbyte(s string, c byte) int
+;
+;    lastindexbyte returns the index of the last instance of c in s, or -1 if c
+;    is not present in s.
+define %int @runtime.lastindexbyte(%string %s, %uint8 %c) {
+entry:
+	%data = extractvalue %string %s, 0
+	%len = extractvalue %string %s, 1
+	br label %loop.cond
+
+loop.cond:
+	%i = phi %int [ %len, %entry ], [ %i.dec, %loop.body ]
+	%i.dec = sub %int %i, 1
+	%cond = icmp sge %int %i.dec, 0
+	br i1 %cond, label %loop.body, label %ret_none
+
+loop.body:
+	%p = getelementptr %uint8, %uint8* %data, %int %i.dec
+	%b = load %uint8, %uint8* %p
+	%found = icmp eq %uint8 %b, %c
+	br i1 %found, label %ret_index, label %loop.cond
+
+ret_index:
+	ret %int %i.dec
+
+ret_none:
+	ret %int -1
+}
+
+; === [ slices ] ===============================================================
+
+; declare void @llvm.memmove.p0i8.p0i8.i64(i8* <dest>, i8* <src>, i64 <len>, i1 <isvolatile>)
+declare void @llvm.memmove.p0i8.p0i8.i64(i8* %dest, i8* %src, i64 %len, i1 %isvolatile)
+
+; Maximum size in bytes of allocations.
+@runtime.maxAlloc = private unnamed_addr constant %uint64 281474976710656 ; 1 << 48
+
+; func runtime.panicmakeslice(msg *string)
+;
+;    panicmakeslice panics with the given makeslice runtime error message.
+define void @runtime.panicmakeslice(%string* %msg) {
+entry:
+	%runtime_error = load %string, %string* @"runtime.str.runtime_error"
+	%s = load %string, %string* %msg
+	%error_msg = call %string @runtime.concatstring2(%string %runtime_error, %string %s)
+	call void @runtime.panicerror(%string %error_msg)
+	unreachable
+}
+
+; func runtime.makeslice(size, len, cap int) unsafe.Pointer
+;
+;    makeslice allocates the zero-initialized backing array of a slice with the
+;    given length and capacity, and element size in bytes. makeslice panics if
+;    the length or capacity is out of range.
+define i8* @runtime.makeslice(%int %size, %int %len, %int %cap) {
+entry:
+	%len_neg = icmp slt %int %len, 0
+	%len_gt_cap = icmp sgt %int %len, %cap
+	%invalid_len = or i1 %len_neg, %len_gt_cap
+	%cap_too_large = call i1 @runtime.toolarge(%int %size, %int %cap)
+	%invalid = or i1 %invalid_len, %cap_too_large
+	br i1 %invalid, label %fail, label %alloc
+
+alloc:
+	%p = call i8* @calloc(%uint64 %cap, %uint64 %size)
+	ret i8* %p
+
+	; Report length out of range if the length is invalid on its own; otherwise
+	; capacity out of range.
+fail:
+	%len_too_large = call i1 @runtime.toolarge(%int %size, %int %len)
+	%bad_len = or i1 %len_neg, %len_too_large
+	br i1 %bad_len, label %fail_len, label %fail_cap
+
+fail_len:
+	call void @runtime.panicmakeslice(%string* @"runtime.str.makeslice_len")
+	unreachable
+
+fail_cap:
+	call void @runtime.panicmakeslice(%string* @"runtime.str.makeslice_cap")
+	unreachable
+}
+
+; func runtime.toolarge(size, n int) bool
+;
+;    toolarge reports whether n elements of the given size in bytes exceed the
+;    maximum allocation size (treating negative n as too large).
+define i1 @runtime.toolarge(%int %size, %int %n) {
+entry:
+	%maxAlloc = load %uint64, %uint64* @runtime.maxAlloc
+	%is_zero = icmp eq %int %size, 0
+	br i1 %is_zero, label %zero, label %nonzero
+
+zero:
+	%neg = icmp slt %int %n, 0
+	ret i1 %neg
+
+nonzero:
+	%max = udiv %uint64 %maxAlloc, %size
+	%too_large = icmp ugt %uint64 %n, %max
+	ret i1 %too_large
+}
+
+; func runtime.nextslicecap(newlen, oldcap int) int
+;
+;    nextslicecap returns the capacity of a grown slice with room for at least
+;    newlen elements, based on the old capacity. Small slices double in size,
+;    while large slices grow by approximately 1.25x.
+define %int @runtime.nextslicecap(%int %newlen, %int %oldcap) {
+entry:
+	%doublecap = add %int %oldcap, %oldcap
+	%gt_double = icmp sgt %int %newlen, %doublecap
+	br i1 %gt_double, label %ret_newlen, label %check_small
+
+check_small:
+	; const threshold = 256
+	%small = icmp slt %int %oldcap, 256
+	br i1 %small, label %ret_double, label %loop
+
+	; Transition from growing 2x for small slices to 1.25x for large slices.
+	;
+	;    for newcap < newlen {
+	;       newcap += (newcap + 3*threshold) / 4
+	;    }
+loop:
+	%newcap = phi %int [ %oldcap, %check_small ], [ %newcap.next, %loop ]
+	%inc_sum = add %int %newcap, 768
+	%inc = sdiv %int %inc_sum, 4
+	%newcap.next = add %int %newcap, %inc
+	%done = icmp uge %int %newcap.next, %newlen
+	br i1 %done, label %check_overflow, label %loop
+
+	; Use newlen if the computation overflowed.
+check_overflow:
+	%overflow = icmp sle %int %newcap.next, 0
+	br i1 %overflow, label %ret_newlen, label %ret_newcap
+
+ret_newcap:
+	ret %int %newcap.next
+
+ret_double:
+	ret %int %doublecap
+
+ret_newlen:
+	ret %int %newlen
+}
+
+; func runtime.appendslice(size int, s slice, p unsafe.Pointer, n int) slice
+;
+;    appendslice appends the n elements at p to s, and returns the resulting
+;    slice. The backing array of s is grown (and copied) if out of capacity.
+;    The elements at p may overlap with the backing array of s.
+define %runtime.slice @runtime.appendslice(%int %size, %runtime.slice %s, i8* %p, %int %n) {
+entry:
+	%data = extractvalue %runtime.slice %s, 0
+	%len = extractvalue %runtime.slice %s, 1
+	%cap = extractvalue %runtime.slice %s, 2
+	%newlen = add %int %len, %n
+	%grow = icmp sgt %int %newlen, %cap
+	br i1 %grow, label %grow_slice, label %append
+
+grow_slice:
+	%newcap = call %int @runtime.nextslicecap(%int %newlen, %int %cap)
+	%newdata = call i8* @runtime.makeslice(%int %size, %int %newlen, %int %newcap)
+	%old_size = mul %int %len, %size
+	call void @llvm.memmove.p0i8.p0i8.i64(i8* %newdata, i8* %data, i64 %old_size, i1 false)
+	br label %append
+
+append:
+	%dst_data = phi i8* [ %data, %entry ], [ %newdata, %grow_slice ]
+	%dst_cap = phi %int [ %cap, %entry ], [ %newcap, %grow_slice ]
+	%offset = mul %int %len, %size
+	%dst = getelementptr i8, i8* %dst_data, %int %offset
+	%n_size = mul %int %n, %size
+	call void @llvm.memmove.p0i8.p0i8.i64(i8* %dst, i8* %p, i64 %n_size, i1 false)
+	%result.0 = insertvalue %runtime.slice zeroinitializer, i8* %dst_data, 0
+	%result.1 = insertvalue %runtime.slice %result.0, %int %newlen, 1
+	%result.2 = insertvalue %runtime.slice %result.1, %int %dst_cap, 2
+	ret %runtime.slice %result.2
+}
+
+; func runtime.slicecopy(dst unsafe.Pointer, dstlen int, src unsafe.Pointer, srclen int, size int) int
+;
+;    slicecopy copies min(dstlen, srclen) elements of the given size in bytes
+;    from src to dst, and returns the number of elements copied. The source and
+;    destination may overlap.
+define %int @runtime.slicecopy(i8* %dst, %int %dstlen, i8* %src, %int %srclen, %int %size) {
+entry:
+	%n = call %int @internal.min(%int %dstlen, %int %srclen)
+	%n_size = mul %int %n, %size
+	call void @llvm.memmove.p0i8.p0i8.i64(i8* %dst, i8* %src, i64 %n_size, i1 false)
+	ret %int %n
+}
+
+; === [ bounds checks ] ========================================================
+
+@"runtime.str.index_range.data" = private unnamed_addr constant [20 x i8] c"index out of range ["
+@"runtime.str.slice_range.data" = private unnamed_addr constant [27 x i8] c"slice bounds out of range ["
+@"runtime.str.slice_range_a.data" = private unnamed_addr constant [28 x i8] c"slice bounds out of range [:"
+@"runtime.str.slice_range_3a.data" = private unnamed_addr constant [29 x i8] c"slice bounds out of range [::"
+@"runtime.str.with_length.data" = private unnamed_addr constant [14 x i8] c"] with length "
+@"runtime.str.with_capacity.data" = private unnamed_addr constant [16 x i8] c"] with capacity "
+@"runtime.str.colon.data" = private unnamed_addr constant [1 x i8] c":"
+@"runtime.str.rbrack.data" = private unnamed_addr constant [1 x i8] c"]"
+@"runtime.str.colon_rbrack.data" = private unnamed_addr constant [2 x i8] c":]"
+@"runtime.str.colon_colon_rbrack.data" = private unnamed_addr constant [3 x i8] c"::]"
+@"runtime.str.minus.data" = private unnamed_addr constant [1 x i8] c"-"
+@"runtime.str.minus" = private unnamed_addr constant %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.minus.data", i64 0, i64 0), %int 1 }
+
+; Format of bounds check error messages, as indexed by bounds check kind.
+;
+;    type boundsFormat struct {
+;       prefix    string // precedes x
+;       infix     string // between x and y
+;       suffix    string // follows y
+;       negsuffix string // follows x if x is negative (y is not printed)
+;    }
+%runtime.boundsformat = type { %string, %string, %string, %string }
+
+@runtime.boundsformats = private unnamed_addr constant [8 x %runtime.boundsformat] [
+	; index
+	%runtime.boundsformat { %string { i8* getelementptr ([20 x i8], [20 x i8]* @"runtime.str.index_range.data", i64 0, i64 0), %int 20 }, %string { i8* getelementptr ([14 x i8], [14 x i8]* @"runtime.str.with_length.data", i64 0, i64 0), %int 14 }, %string zeroinitializer, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.rbrack.data", i64 0, i64 0), %int 1 } },
+	; slice_alen
+	%runtime.boundsformat { %string { i8* getelementptr ([28 x i8], [28 x i8]* @"runtime.str.slice_range_a.data", i64 0, i64 0), %int 28 }, %string { i8* getelementptr ([14 x i8], [14 x i8]* @"runtime.str.with_length.data", i64 0, i64 0), %int 14 }, %string zeroinitializer, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.rbrack.data", i64 0, i64 0), %int 1 } },
+	; slice_acap
+	%runtime.boundsformat { %string { i8* getelementptr ([28 x i8], [28 x i8]* @"runtime.str.slice_range_a.data", i64 0, i64 0), %int 28 }, %string { i8* getelementptr ([16 x i8], [16 x i8]* @"runtime.str.with_capacity.data", i64 0, i64 0), %int 16 }, %string zeroinitializer, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.rbrack.data", i64 0, i64 0), %int 1 } },
+	; slice_b
+	%runtime.boundsformat { %string { i8* getelementptr ([27 x i8], [27 x i8]* @"runtime.str.slice_range.data", i64 0, i64 0), %int 27 }, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.colon.data", i64 0, i64 0), %int 1 }, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.rbrack.data", i64 0, i64 0), %int 1 }, %string { i8* getelementptr ([2 x i8], [2 x i8]* @"runtime.str.colon_rbrack.data", i64 0, i64 0), %int 2 } },
+	; slice3_alen
+	%runtime.boundsformat { %string { i8* getelementptr ([29 x i8], [29 x i8]* @"runtime.str.slice_range_3a.data", i64 0, i64 0), %int 29 }, %string { i8* getelementptr ([14 x i8], [14 x i8]* @"runtime.str.with_length.data", i64 0, i64 0), %int 14 }, %string zeroinitializer, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.rbrack.data", i64 0, i64 0), %int 1 } },
+	; slice3_acap
+	%runtime.boundsformat { %string { i8* getelementptr ([29 x i8], [29 x i8]* @"runtime.str.slice_range_3a.data", i64 0, i64 0), %int 29 }, %string { i8* getelementptr ([16 x i8], [16 x i8]* @"runtime.str.with_capacity.data", i64 0, i64 0), %int 16 }, %string zeroinitializer, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.rbrack.data", i64 0, i64 0), %int 1 } },
+	; slice3_b
+	%runtime.boundsformat { %string { i8* getelementptr ([28 x i8], [28 x i8]* @"runtime.str.slice_range_a.data", i64 0, i64 0), %int 28 }, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.colon.data", i64 0, i64 0), %int 1 }, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.rbrack.data", i64 0, i64 0), %int 1 }, %string { i8* getelementptr ([2 x i8], [2 x i8]* @"runtime.str.colon_rbrack.data", i64 0, i64 0), %int 2 } },
+	; slice3_c
+	%runtime.boundsformat { %string { i8* getelementptr ([27 x i8], [27 x i8]* @"runtime.str.slice_range.data", i64 0, i64 0), %int 27 }, %string { i8* getelementptr ([1 x i8], [1 x i8]* @"runtime.str.colon.data", i64 0, i64 0), %int 1 }, %string { i8* getelementptr ([2 x i8], [2 x i8]* @"runtime.str.colon_rbrack.data", i64 0, i64 0), %int 2 }, %string { i8* getelementptr ([3 x i8], [3 x i8]* @"runtime.str.colon_colon_rbrack.data", i64 0, i64 0), %int 3 } }
+]
+
+; func runtime.uitoa(v uint64) string
+;
+;    uitoa returns the decimal representation of v.
+define %string @runtime.uitoa(%uint64 %v) {
+entry:
+	%mem = call i8* @calloc(i64 1, i64 20)
+	%buf = bitcast i8* %mem to [20 x i8]*
+	br label %loop
+
+loop:
+	%i = phi %int [ 20, %entry ], [ %i.dec, %loop ]
+	%x = phi %uint64 [ %v, %entry ], [ %x.div, %loop ]
+	%i.dec = sub %int %i, 1
+	%digit = urem %uint64 %x, 10
+	%digit8 = trunc %uint64 %digit to i8
+	%c = add i8 %digit8, 48 ; '0'
+	%p = getelementptr [20 x i8], [20 x i8]* %buf, i64 0, %int %i.dec
+	store i8 %c, i8* %p
+	%x.div = udiv %uint64 %x, 10
+	%more = icmp ne %uint64 %x.div, 0
+	br i1 %more, label %loop, label %done
+
+done:
+	%n = sub %int 20, %i.dec
+	%s.0 = insertvalue %string zeroinitializer, i8* %p, 0
+	%s.1 = insertvalue %string %s.0, %int %n, 1
+	ret %string %s.1
+}
+
+; func runtime.itoa(v int64) string
+;
+;    itoa returns the decimal representation of v.
+define %string @runtime.itoa(%int64 %v) {
+entry:
+	%neg = icmp slt %int64 %v, 0
+	%neg_v = sub %int64 0, %v
+	%abs = select i1 %neg, %int64 %neg_v, %int64 %v
+	%digits = call %string @runtime.uitoa(%uint64 %abs)
+	br i1 %neg, label %ret_neg, label %ret_pos
+
+ret_neg:
+	%minus = load %string, %string* @"runtime.str.minus"
+	%s = call %string @runtime.concatstring2(%string %minus, %string %digits)
+	ret %string %s
+
+ret_pos:
+	ret %string %digits
+}
+
+; func runtime.printuint(v uint64)
+;
+;    printuint writes the decimal representation of v to standard error.
+define void @runtime.printuint(%uint64 %v) {
+entry:
+	%s = call %string @runtime.uitoa(%uint64 %v)
+	call void @runtime.printstring(%string %s)
+	ret void
+}
+
+; func runtime.printint(v int64)
+;
+;    printint writes the decimal representation of v to standard error.
+define void @runtime.printint(%int64 %v) {
+entry:
+	%s = call %string @runtime.itoa(%int64 %v)
+	call void @runtime.printstring(%string %s)
+	ret void
+}
+
+; func runtime.panicbounds(x int, signed bool, y int, kind uint8)
+;
+;    panicbounds panics on a failed bounds check of the index x against y. The
+;    signed flag reports whether x has signed integer type, and kind specifies
+;    the kind of bounds check (as used to index runtime.boundsformats).
+;
+;       panic: runtime error: index out of range [5] with length 3
+;       panic: runtime error: slice bounds out of range [:5] with capacity 3
+;       panic: runtime error: slice bounds out of range [-1:]
+define void @runtime.panicbounds(%int %x, %bool %signed, %int %y, %uint8 %kind) {
+entry:
+	%runtime_error = load %string, %string* @"runtime.str.runtime_error"
+	%format_ptr = getelementptr [8 x %runtime.boundsformat], [8 x %runtime.boundsformat]* @runtime.boundsformats, i64 0, %uint8 %kind
+	%format = load %runtime.boundsformat, %runtime.boundsformat* %format_ptr
+	%prefix = extractvalue %runtime.boundsformat %format, 0
+	%msg.prefix = call %string @runtime.concatstring2(%string %runtime_error, %string %prefix)
+	%x_neg = icmp slt %int %x, 0
+	%neg = and i1 %signed, %x_neg
+	br i1 %neg, label %format_neg, label %format_pos
+
+format_neg:
+	%x_signed = call %string @runtime.itoa(%int64 %x)
+	%negsuffix = extractvalue %runtime.boundsformat %format, 3
+	%msg.neg.0 = call %string @runtime.concatstring2(%string %msg.prefix, %string %x_signed)
+	%msg.neg = call %string @runtime.concatstring2(%string %msg.neg.0, %string %negsuffix)
+	call void @runtime.panicerror(%string %msg.neg)
+	unreachable
+
+format_pos:
+	%x_unsigned = call %string @runtime.uitoa(%uint64 %x)
+	%infix = extractvalue %runtime.boundsformat %format, 1
+	%y_str = call %string @runtime.itoa(%int64 %y)
+	%suffix = extractvalue %runtime.boundsformat %format, 2
+	%msg.pos.0 = call %string @runtime.concatstring2(%string %msg.prefix, %string %x_unsigned)
+	%msg.pos.1 = call %string @runtime.concatstring2(%string %msg.pos.0, %string %infix)
+	%msg.pos.2 = call %string @runtime.concatstring2(%string %msg.pos.1, %string %y_str)
+	%msg.pos = call %string @runtime.concatstring2(%string %msg.pos.2, %string %suffix)
+	call void @runtime.panicerror(%string %msg.pos)
+	unreachable
+}
+
+; === [ arithmetic checks ] ====================================================
+
+@"runtime.str.divide.data" = private unnamed_addr constant [22 x i8] c"integer divide by zero"
+@"runtime.str.divide" = private unnamed_addr constant %string { i8* getelementptr ([22 x i8], [22 x i8]* @"runtime.str.divide.data", i64 0, i64 0), %int 22 }
+@"runtime.str.shift.data" = private unnamed_addr constant [21 x i8] c"negative shift amount"
+@"runtime.str.shift" = private unnamed_addr constant %string { i8* getelementptr ([21 x i8], [21 x i8]* @"runtime.str.shift.data", i64 0, i64 0), %int 21 }
+
+; func runtime.panicdivide()
+;
+;    panicdivide panics on integer division by zero.
+;
+;       panic: runtime error: integer divide by zero
+define void @runtime.panicdivide() {
+entry:
+	%runtime_error = load %string, %string* @"runtime.str.runtime_error"
+	%divide = load %string, %string* @"runtime.str.divide"
+	%msg = call %string @runtime.concatstring2(%string %runtime_error, %string %divide)
+	call void @runtime.panicerror(%string %msg)
+	unreachable
+}
+
+; func runtime.panicshift()
+;
+;    panicshift panics on shifts by a negative shift count.
+;
+;       panic: runtime error: negative shift amount
+define void @runtime.panicshift() {
+entry:
+	%runtime_error = load %string, %string* @"runtime.str.runtime_error"
+	%shift = load %string, %string* @"runtime.str.shift"
+	%msg = call %string @runtime.concatstring2(%string %runtime_error, %string %shift)
+	call void @runtime.panicerror(%string %msg)
+	unreachable
+}
+
+; === [ complex numbers ] ======================================================
+
+; declare double @llvm.fabs.f64(double <x>)
+declare double @llvm.fabs.f64(double %x)
+; declare double @llvm.copysign.f64(double <mag>, double <sgn>)
+declare double @llvm.copysign.f64(double %mag, double %sgn)
+
+; func runtime.complex128div(n, m complex128) complex128
+;
+;    complex128div returns the complex quotient n/m, using the algorithm of the
+;    Go runtime; Smith's algorithm, with the result corrected to infinities and
+;    zeros if both parts are NaN (as per C99 Annex G.5.1).
+define %complex128 @runtime.complex128div(%complex128 %n, %complex128 %m) {
+entry:
+	%a = extractvalue %complex128 %n, 0
+	%b = extractvalue %complex128 %n, 1
+	%c = extractvalue %complex128 %m, 0
+	%d = extractvalue %complex128 %m, 1
+	%abs_a = call double @llvm.fabs.f64(double %a)
+	%abs_b = call double @llvm.fabs.f64(double %b)
+	%abs_c = call double @llvm.fabs.f64(double %c)
+	%abs_d = call double @llvm.fabs.f64(double %d)
+	; if abs(c) >= abs(d)
+	%real_major = fcmp oge double %abs_c, %abs_d
+	br i1 %real_major, label %smith.real, label %smith.imag
+
+smith.real:
+	; ratio := d / c
+	; denom := c + ratio*d
+	; e = (a + b*ratio) / denom
+	; f = (b - a*ratio) / denom
+	%ratio_real = fdiv double %d, %c
+	%ratio_d = fmul double %ratio_real, %d
+	%denom_real = fadd double %c, %ratio_d
+	%b_ratio = fmul double %b, %ratio_real
+	%e_num_real = fadd double %a, %b_ratio
+	%e_real = fdiv double %e_num_real, %denom_real
+	%a_ratio = fmul double %a, %ratio_real
+	%f_num_real = fsub double %b, %a_ratio
+	%f_real = fdiv double %f_num_real, %denom_real
+	br label %smith.done
+
+smith.imag:
+	; ratio := c / d
+	; denom := d + ratio*c
+	; e = (a*ratio + b) / denom
+	; f = (b*ratio - a) / denom
+	%ratio_imag = fdiv double %c, %d
+	%ratio_c = fmul double %ratio_imag, %c
+	%denom_imag = fadd double %d, %ratio_c
+	%a_ratio_imag = fmul double %a, %ratio_imag
+	%e_num_imag = fadd double %a_ratio_imag, %b
+	%e_imag = fdiv double %e_num_imag, %denom_imag
+	%b_ratio_imag = fmul double %b, %ratio_imag
+	%f_num_imag = fsub double %b_ratio_imag, %a
+	%f_imag = fdiv double %f_num_imag, %denom_imag
+	br label %smith.done
+
+smith.done:
+	%e = phi double [ %e_real, %smith.real ], [ %e_imag, %smith.imag ]
+	%f = phi double [ %f_real, %smith.real ], [ %f_imag, %smith.imag ]
+	; if isNaN(e) && isNaN(f)
+	%e_nan = fcmp uno double %e, %e
+	%f_nan = fcmp uno double %f, %f
+	%nan = and i1 %e_nan, %f_nan
+	br i1 %nan, label %fix.zero, label %exit
+
+fix.zero:
+	; case m == 0 && (!isNaN(a) || !isNaN(b))
+	%c_zero = fcmp oeq double %c, 0.0
+	%d_zero = fcmp oeq double %d, 0.0
+	%m_zero = and i1 %c_zero, %d_zero
+	%a_num = fcmp ord double %a, %a
+	%b_num = fcmp ord double %b, %b
+	%n_num = or i1 %a_num, %b_num
+	%zero_case = and i1 %m_zero, %n_num
+	br i1 %zero_case, label %fix.zero.body, label %fix.inf_n
+
+fix.zero.body:
+	; e = copysign(inf, c) * a
+	; f = copysign(inf, c) * b
+	%inf_c = call double @llvm.copysign.f64(double 0x7FF0000000000000, double %c)
+	%e_zero = fmul double %inf_c, %a
+	%f_zero = fmul double %inf_c, %b
+	br label %exit
+
+fix.inf_n:
+	; case (isInf(a) || isInf(b)) && isFinite(c) && isFinite(d)
+	%a_inf = fcmp oeq double %abs_a, 0x7FF0000000000000
+	%b_inf = fcmp oeq double %abs_b, 0x7FF0000000000000
+	%n_inf = or i1 %a_inf, %b_inf
+	%c_finite = fcmp olt double %abs_c, 0x7FF0000000000000
+	%d_finite = fcmp olt double %abs_d, 0x7FF0000000000000
+	%m_finite = and i1 %c_finite, %d_finite
+	%inf_n_case = and i1 %n_inf, %m_finite
+	br i1 %inf_n_case, label %fix.inf_n.body, label %fix.inf_m
+
+fix.inf_n.body:
+	; a = inf2one(a)
+	; b = inf2one(b)
+	; e = inf * (a*c + b*d)
+	; f = inf * (b*c - a*d)
+	%a_one_mag = select i1 %a_inf, double 1.0, double 0.0
+	%a_one = call double @llvm.copysign.f64(double %a_one_mag, double %a)
+	%b_one_mag = select i1 %b_inf, double 1.0, double 0.0
+	%b_one = call double @llvm.copysign.f64(double %b_one_mag, double %b)
+	%ac_inf_n = fmul double %a_one, %c
+	%bd_inf_n = fmul double %b_one, %d
+	%e_sum_inf_n = fadd double %ac_inf_n, %bd_inf_n
+	%e_inf_n = fmul double 0x7FF0000000000000, %e_sum_inf_n
+	%bc_inf_n = fmul double %b_one, %c
+	%ad_inf_n = fmul double %a_one, %d
+	%f_diff_inf_n = fsub double %bc_inf_n, %ad_inf_n
+	%f_inf_n = fmul double 0x7FF0000000000000, %f_diff_inf_n
+	br label %exit
+
+fix.inf_m:
+	; case (isInf(c) || isInf(d)) && isFinite(a) && isFinite(b)
+	%c_inf = fcmp oeq double %abs_c, 0x7FF0000000000000
+	%d_inf = fcmp oeq double %abs_d, 0x7FF0000000000000
+	%m_inf = or i1 %c_inf, %d_inf
+	%a_finite = fcmp olt double %abs_a, 0x7FF0000000000000
+	%b_finite = fcmp olt double %abs_b, 0x7FF0000000000000
+	%n_finite = and i1 %a_finite, %b_finite
+	%inf_m_case = and i1 %m_inf, %n_finite
+	br i1 %inf_m_case, label %fix.inf_m.body, label %exit
+
+fix.inf_m.body:
+	; c = inf2one(c)
+	; d = inf2one(d)
+	; e = 0 * (a*c + b*d)
+	; f = 0 * (b*c - a*d)
+	%c_one_mag = select i1 %c_inf, double 1.0, double 0.0
+	%c_one = call double @llvm.copysign.f64(double %c_one_mag, double %c)
+	%d_one_mag = select i1 %d_inf, double 1.0, double 0.0
+	%d_one = call double @llvm.copysign.f64(double %d_one_mag, double %d)
+	%ac_inf_m = fmul double %a, %c_one
+	%bd_inf_m = fmul double %b, %d_one
+	%e_sum_inf_m = fadd double %ac_inf_m, %bd_inf_m
+	%e_inf_m = fmul double 0.0, %e_sum_inf_m
+	%bc_inf_m = fmul double %b, %c_one
+	%ad_inf_m = fmul double %a, %d_one
+	%f_diff_inf_m = fsub double %bc_inf_m, %ad_inf_m
+	%f_inf_m = fmul double 0.0, %f_diff_inf_m
+	br label %exit
+
+exit:
+	%real = phi double [ %e, %smith.done ], [ %e_zero, %fix.zero.body ], [ %e_inf_n, %fix.inf_n.body ], [ %e, %fix.inf_m ], [ %e_inf_m, %fix.inf_m.body ]
+	%imag = phi double [ %f, %smith.done ], [ %f_zero, %fix.zero.body ], [ %f_inf_n, %fix.inf_n.body ], [ %f, %fix.inf_m ], [ %f_inf_m, %fix.inf_m.body ]
+	%z_real = insertvalue %complex128 zeroinitializer, double %real, 0
+	%z = insertvalue %complex128 %z_real, double %imag, 1
+	ret %complex128 %z
+}
+
+; === [ defer ] ================================================================
+
+; Top of the defer stack of the goroutine; a linked list of the defer records of
+; pending deferred calls, most recently deferred first.
+@runtime.defers = global %runtime._defer* null
+
+; func runtime.deferproc(fn func(arg unsafe.Pointer), arg, frame unsafe.Pointer)
+;
+;    deferproc pushes a heap-allocated defer record of the deferred call fn(arg)
+;    to the defer stack. The jump buffer frame of the deferring function (see
+;    setjmp) is used to resume execution in the deferring function if the
+;    deferred call recovers a panic.
+define void @runtime.deferproc(void (i8*)* %fn, i8* %arg, i8* %frame) {
+entry:
+	%size = ptrtoint %runtime._defer* getelementptr (%runtime._defer, %runtime._defer* null, i64 1) to i64
+	%mem = call i8* @calloc(i64 1, i64 %size)
+	%d = bitcast i8* %mem to %runtime._defer*
+	%top = load %runtime._defer*, %runtime._defer** @runtime.defers
+	%link_ptr = getelementptr %runtime._defer, %runtime._defer* %d, i64 0, i32 0
+	store %runtime._defer* %top, %runtime._defer** %link_ptr
+	%fn_ptr = getelementptr %runtime._defer, %runtime._defer* %d, i64 0, i32 1
+	store void (i8*)* %fn, void (i8*)** %fn_ptr
+	%arg_ptr = getelementptr %runtime._defer, %runtime._defer* %d, i64 0, i32 2
+	store i8* %arg, i8** %arg_ptr
+	%frame_ptr = getelementptr %runtime._defer, %runtime._defer* %d, i64 0, i32 3
+	store i8* %frame, i8** %frame_ptr
+	store %runtime._defer* %d, %runtime._defer** @runtime.defers
+	ret void
+}
+
+; func runtime.deferreturn(base *_defer)
+;
+;    deferreturn runs the pending deferred calls pushed to the defer stack above
+;    base, most recently deferred first. Defer records are popped before their
+;    deferred call is run. Deferred calls run on return cannot recover panics.
+define void @runtime.deferreturn(%runtime._defer* %base) {
+entry:
+	br label %loop
+
+loop:
+	%top = load %runtime._defer*, %runtime._defer** @runtime.defers
+	%done = icmp eq %runtime._defer* %top, %base
+	br i1 %done, label %exit, label %call
+
+call:
+	%link_ptr = getelementptr %runtime._defer, %runtime._defer* %top, i64 0, i32 0
+	%link = load %runtime._defer*, %runtime._defer** %link_ptr
+	store %runtime._defer* %link, %runtime._defer** @runtime.defers
+	%fn_ptr = getelementptr %runtime._defer, %runtime._defer* %top, i64 0, i32 1
+	%fn = load void (i8*)*, void (i8*)** %fn_ptr
+	%arg_ptr = getelementptr %runtime._defer, %runtime._defer* %top, i64 0, i32 2
+	%arg = load i8*, i8** %arg_ptr
+	call void %fn(i8* %arg)
+	br label %loop
+
+exit:
+	ret void
+}